@@ -0,0 +1,482 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"quiz_go/internal/config"
+	"quiz_go/internal/perfil"
+	"quiz_go/internal/quiz"
+	"quiz_go/internal/sala"
+	"quiz_go/internal/servidor"
+	"quiz_go/internal/terminal"
+	"quiz_go/internal/ui"
+)
+
+type comando struct {
+	nome      string
+	descricao string
+	executar  func(cfg config.Config, args []string) error
+}
+
+var comandos = []comando{
+	{"play", "joga um quiz com o modo, a dificuldade e a categoria escolhidos", comandoPlay},
+	{"stats", "mostra as estatísticas salvas", comandoStats},
+	{"records", "mostra ou exporta os recordes de cada modo, com os perfis e as datas", comandoRecords},
+	{"generate", "gera questões com IA e grava em um arquivo do banco", comandoGenerate},
+	{"import", "valida arquivos de questões e os copia para o banco local", comandoImport},
+	{"validate", "valida arquivos de questões sem importá-los", comandoValidate},
+	{"serve", "inicia a página e a API HTTP/JSON do quiz", comandoServe},
+	{"room", "partida em grupo num servidor ('room host' abre a sala, 'room join <código>' entra)", comandoRoom},
+	{"profile", "gerencia os perfis dos jogadores ('profile list', 'create', 'rename', 'delete', 'merge', 'use')", comandoProfile},
+	{"config", "mostra a configuração efetiva ('config show')", comandoConfig},
+}
+
+// errUso indica que a mensagem de uso já foi exibida pelo FlagSet.
+var errUso = errors.New("uso incorreto")
+
+// executarComando roda o subcomando em args[0] e retorna o código de saída.
+func executarComando(cfg config.Config, args []string) int {
+	nome := args[0]
+	if nome == "help" || nome == "-h" || nome == "--help" {
+		mostrarUso()
+		return 0
+	}
+
+	for _, c := range comandos {
+		if c.nome != nome {
+			continue
+		}
+		err := c.executar(cfg, args[1:])
+		switch {
+		case err == nil, errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUso):
+			return 2
+		default:
+			fmt.Fprintln(os.Stderr, ui.Red("❌ "+err.Error()))
+			return 1
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "comando desconhecido: %s\n\n", nome)
+	mostrarUso()
+	return 2
+}
+
+func mostrarUso() {
+	fmt.Fprintln(os.Stderr, "Uso: quiz_go [opções globais] [comando] [opções]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Sem comando, abre o menu interativo.")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Comandos:")
+	for _, c := range comandos {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.nome, c.descricao)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Opções globais:")
+	fmt.Fprintf(os.Stderr, "  --%-36s %s\n", "config <arquivo>", "arquivo de configuração (padrão: "+config.ArquivoPadrao()+")")
+	fmt.Fprintf(os.Stderr, "  --%-36s %s\n", "profile <nome>", "perfil do jogador (padrão: o último usado)")
+	for _, v := range config.Padrao().Valores() {
+		fmt.Fprintf(os.Stderr, "  --%-36s %s\n", config.NomeFlag(v.Chave)+" <valor>", v.Descricao)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Use 'quiz_go <comando> -h' para ver as opções de cada comando.")
+}
+
+func novoFlagSet(nome, argumentos string) *flag.FlagSet {
+	fs := flag.NewFlagSet(nome, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Uso: %s\n\nOpções:\n", strings.TrimSpace("quiz_go "+nome+" [opções] "+argumentos))
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func analisarFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUso
+	}
+	return nil
+}
+
+func comandoPlay(cfg config.Config, args []string) error {
+	fs := novoFlagSet("play", "")
+	modo := fs.String("mode", string(quiz.ModoTodas), "modo de jogo: "+listarModos())
+	dificuldade := fs.String("difficulty", "", "dificuldade: facil, medio ou dificil (padrão do modo se vazio)")
+	categoria := fs.String("category", "", "categoria das questões (todas se vazio)")
+	tipo := fs.String("type", "", "tipo das questões: "+strings.Join(quiz.Tipos, ", ")+" (todos se vazio)")
+	quantidade := fs.Int("n", 0, "quantidade de questões (padrão do modo se 0)")
+	if err := analisarFlags(fs, args); err != nil {
+		return err
+	}
+
+	cat, err := quiz.ValidarCategoria(*categoria)
+	if err != nil {
+		return err
+	}
+
+	sel := quiz.Selecao{
+		Modo:        quiz.Modo(*modo),
+		Dificuldade: *dificuldade,
+		Categoria:   cat,
+		Tipo:        *tipo,
+		Quantidade:  *quantidade,
+	}
+	if err := sel.Validar(); err != nil {
+		return err
+	}
+	if err := abrirPerfil(&cfg); err != nil {
+		return err
+	}
+
+	// Ctrl+C interrompe a geração; as questões que faltam vêm do banco.
+	ctx, cancelar := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancelar()
+
+	q := quiz.NewQuiz(cfg)
+	sessao := q.IniciarSessao(ctx, sel)
+	if sessao.Total() == 0 {
+		return fmt.Errorf("nenhuma questão encontrada para esta seleção")
+	}
+
+	terminal.ExecutarQuiz(sessao)
+	return nil
+}
+
+func listarModos() string {
+	nomes := make([]string, len(quiz.Modos))
+	for i, m := range quiz.Modos {
+		nomes[i] = string(m)
+	}
+	return strings.Join(nomes, ", ")
+}
+
+func comandoStats(cfg config.Config, args []string) error {
+	fs := novoFlagSet("stats", "")
+	if err := analisarFlags(fs, args); err != nil {
+		return err
+	}
+	if err := abrirPerfil(&cfg); err != nil {
+		return err
+	}
+
+	q := quiz.NewQuiz(cfg)
+	q.MostrarEstatisticas()
+	return nil
+}
+
+func comandoGenerate(cfg config.Config, args []string) error {
+	fs := novoFlagSet("generate", "")
+	quantidade := fs.Int("n", 5, "quantidade de questões a gerar")
+	dificuldade := fs.String("difficulty", "", "dificuldade: facil, medio ou dificil (aleatória se vazio)")
+	categoria := fs.String("category", "", "categoria das questões (aleatória se vazio)")
+	tipo := fs.String("type", "", "tipo das questões: "+strings.Join(quiz.Tipos, ", ")+" (múltipla escolha se vazio)")
+	primeiroID := fs.Int("first-id", 0, "ID da primeira questão gerada (0 usa o próximo ID livre do banco local)")
+	saida := fs.String("o", "", "arquivo de saída .json ou .yaml (padrão: questoes/geradas-<data>.json)")
+	if err := analisarFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *quantidade <= 0 {
+		return fmt.Errorf("a quantidade deve ser positiva")
+	}
+	sel := quiz.Selecao{Modo: quiz.ModoIAPersonalizado, Dificuldade: *dificuldade, Tipo: *tipo, Quantidade: *quantidade}
+	if err := sel.Validar(); err != nil {
+		return err
+	}
+	cat, err := quiz.ValidarCategoria(*categoria)
+	if err != nil {
+		return err
+	}
+
+	arquivo := *saida
+	if arquivo == "" {
+		arquivo = filepath.Join(quiz.BancoPadrao, "geradas-"+time.Now().Format("20060102-150405")+".json")
+	}
+
+	// Ctrl+C interrompe a geração e salva o que já ficou pronto.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	q := quiz.NewQuiz(cfg)
+	questoes, err := q.GerarQuestoesIA(ctx, *quantidade, *dificuldade, cat, *tipo)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.Yellow("⚠️  Algumas questões não foram geradas:"))
+		fmt.Fprintln(os.Stderr, err)
+	}
+	q.MostrarAutoverificacao()
+	if len(questoes) == 0 {
+		return fmt.Errorf("nenhuma questão foi gerada")
+	}
+
+	id := *primeiroID
+	if id <= 0 {
+		id = proximoIDLivre(cfg)
+	}
+	for i := range questoes {
+		questoes[i].ID = id + i
+	}
+
+	if err := quiz.SalvarBanco(arquivo, questoes); err != nil {
+		return fmt.Errorf("erro ao salvar questões: %v", err)
+	}
+	fmt.Printf("%s %d questões gravadas em %s\n", ui.Green("✅"), len(questoes), arquivo)
+	return nil
+}
+
+// proximoIDLivre retorna o ID seguinte ao maior usado no banco local,
+// ignorando a faixa reservada ao pacote embutido.
+func proximoIDLivre(cfg config.Config) int {
+	questoes, _ := quiz.CarregarBanco(quiz.ResolverCaminhosBanco(cfg.Banco)...)
+	maior := 0
+	for _, questao := range questoes {
+		if questao.ID > maior && questao.ID < quiz.PrimeiroIDPacote {
+			maior = questao.ID
+		}
+	}
+	return maior + 1
+}
+
+func comandoImport(cfg config.Config, args []string) error {
+	fs := novoFlagSet("import", "arquivo...")
+	destino := fs.String("dest", quiz.BancoPadrao, "diretório do banco local")
+	if err := analisarFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errUso
+	}
+
+	var bancoLocal []string
+	if _, err := os.Stat(*destino); err == nil {
+		bancoLocal = append(bancoLocal, *destino)
+	}
+	existentes, err := quiz.CarregarBancoCompleto(bancoLocal...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.Yellow("⚠️  O banco local já contém problemas:"))
+		fmt.Fprintln(os.Stderr, err)
+	}
+	ids := make(map[int]bool, len(existentes))
+	for _, questao := range existentes {
+		ids[questao.ID] = true
+	}
+
+	novas, err := quiz.CarregarBanco(fs.Args()...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.Yellow("⚠️  Registros ignorados:"))
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	var importadas []quiz.Questao
+	for _, questao := range novas {
+		if ids[questao.ID] {
+			fmt.Fprintf(os.Stderr, "ID %d já existe no banco, questão ignorada: %s\n", questao.ID, questao.Questao)
+			continue
+		}
+		importadas = append(importadas, questao)
+	}
+	if len(importadas) == 0 {
+		return fmt.Errorf("nenhuma questão importada")
+	}
+
+	arquivo := filepath.Join(*destino, "importadas-"+time.Now().Format("20060102-150405")+".json")
+	if err := quiz.SalvarBanco(arquivo, importadas); err != nil {
+		return fmt.Errorf("erro ao salvar questões: %v", err)
+	}
+	fmt.Printf("%s %d questões importadas para %s\n", ui.Green("✅"), len(importadas), arquivo)
+	return nil
+}
+
+func comandoValidate(cfg config.Config, args []string) error {
+	fs := novoFlagSet("validate", "[arquivo ou diretório...]")
+	if err := analisarFlags(fs, args); err != nil {
+		return err
+	}
+
+	caminhos := fs.Args()
+	if len(caminhos) == 0 {
+		caminhos = quiz.ResolverCaminhosBanco(cfg.Banco)
+	}
+
+	questoes, err := quiz.CarregarBancoCompleto(caminhos...)
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf("banco de questões inválido")
+	}
+
+	fmt.Printf("%s %d questões válidas (incluindo o pacote embutido)\n", ui.Green("✅"), len(questoes))
+	return nil
+}
+
+func comandoServe(cfg config.Config, args []string) error {
+	fs := novoFlagSet("serve", "")
+	endereco := fs.String("addr", cfg.EnderecoServidor, "endereço em que o servidor escuta")
+	if err := analisarFlags(fs, args); err != nil {
+		return err
+	}
+	if err := abrirPerfil(&cfg); err != nil {
+		return err
+	}
+
+	q := quiz.NewQuiz(cfg)
+	api := servidor.Novo(q)
+	srv := &http.Server{Addr: *endereco, Handler: api}
+
+	// Ctrl+C encerra o servidor; as respostas das sessões em andamento
+	// ficam no histórico.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		desligar, cancelar := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelar()
+		_ = srv.Shutdown(desligar)
+	}()
+
+	fmt.Printf("%s Quiz no navegador em http://%s/ e API em /api/ (Ctrl+C para sair)\n", ui.Green("🌐"), *endereco)
+	err := srv.ListenAndServe()
+	api.Fechar()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func comandoRoom(cfg config.Config, args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "host":
+			return comandoRoomHost(cfg, args[1:])
+		case "join":
+			return comandoRoomJoin(cfg, args[1:])
+		}
+	}
+	fmt.Fprintln(os.Stderr, "Uso: quiz_go room host [opções]")
+	fmt.Fprintln(os.Stderr, "     quiz_go room join [opções] <código>")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "A sala fica num servidor iniciado com 'quiz_go serve'.")
+	return errUso
+}
+
+func comandoRoomHost(cfg config.Config, args []string) error {
+	fs := novoFlagSet("room host", "")
+	servidorURL := fs.String("server", "http://"+cfg.EnderecoServidor, "endereço do servidor do quiz")
+	modo := fs.String("mode", string(quiz.ModoRapido), "modo de jogo: all, quick, hard ou os modos de IA")
+	dificuldade := fs.String("difficulty", "", "dificuldade: facil, medio ou dificil (padrão do modo se vazio)")
+	categoria := fs.String("category", "", "categoria das questões (todas se vazio)")
+	tipo := fs.String("type", "", "tipo das questões: "+strings.Join(quiz.Tipos, ", ")+" (todos se vazio)")
+	quantidade := fs.Int("n", 0, "quantidade de questões (padrão do modo se 0)")
+	arquivo := fs.String("file", "", "arquivo de questões (JSON ou YAML) usado no lugar da seleção, na ordem")
+	tempo := fs.Duration("time", sala.TempoPadrao, "tempo para responder cada questão")
+	pausa := fs.Duration("pause", sala.PausaPadrao, "tempo do placar entre as questões")
+	if err := analisarFlags(fs, args); err != nil {
+		return err
+	}
+
+	cat, err := quiz.ValidarCategoria(*categoria)
+	if err != nil {
+		return err
+	}
+
+	op := sala.Opcoes{
+		Selecao: quiz.Selecao{
+			Modo:        quiz.Modo(*modo),
+			Dificuldade: *dificuldade,
+			Categoria:   cat,
+			Tipo:        *tipo,
+			Quantidade:  *quantidade,
+		},
+		TempoQuestao: *tempo,
+		Pausa:        *pausa,
+	}
+	if *arquivo != "" {
+		questoes, err := quiz.CarregarBanco(*arquivo)
+		if err != nil {
+			return err
+		}
+		if len(questoes) == 0 {
+			return fmt.Errorf("nenhuma questão em %s", *arquivo)
+		}
+		op.Questoes = questoes
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	codigo, chave, err := servidor.CriarSala(ctx, *servidorURL, op)
+	if err != nil {
+		return fmt.Errorf("não foi possível abrir a sala: %v", err)
+	}
+	c, err := servidor.AssistirSala(ctx, *servidorURL, codigo, chave)
+	if err != nil {
+		return err
+	}
+	defer c.Fechar()
+	return terminal.ApresentarSala(ctx, c, codigo)
+}
+
+func comandoRoomJoin(cfg config.Config, args []string) error {
+	fs := novoFlagSet("room join", "<código>")
+	servidorURL := fs.String("server", "http://"+cfg.EnderecoServidor, "endereço do servidor do quiz")
+	nome := fs.String("name", "", "seu nome no placar (padrão: o nome do perfil)")
+	if err := analisarFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUso
+	}
+	if *nome == "" {
+		*nome = cfg.Perfil
+	}
+	if *nome == "" {
+		*nome = perfil.Perfis{Dir: cfg.DiretorioDados}.Ultimo()
+	}
+	if *nome == "" {
+		*nome = terminal.Perguntar("Seu nome no placar:")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c, err := servidor.EntrarSala(ctx, *servidorURL, fs.Arg(0), *nome)
+	if err != nil {
+		return fmt.Errorf("não foi possível entrar na sala: %v", err)
+	}
+	defer c.Fechar()
+	return terminal.JogarSala(ctx, c, strings.TrimSpace(*nome))
+}
+
+func comandoConfig(cfg config.Config, args []string) error {
+	fs := novoFlagSet("config", "show")
+	if err := analisarFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || fs.Arg(0) != "show" {
+		fs.Usage()
+		return errUso
+	}
+	if err := abrirPerfil(&cfg); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Arquivo de configuração: %s\n", ui.Cyan("⚙️"), cfg.Arquivo)
+	fmt.Printf("%s Perfil: %s (%s)\n\n", ui.Cyan("👤"), cfg.Perfil, cfg.ArquivoPerfil)
+	for _, v := range cfg.Valores() {
+		fmt.Printf("%-26s = %-36s %s\n", v.Chave, v.Valor, ui.Blue("("+v.Fonte+")"))
+	}
+	return nil
+}
@@ -1,55 +1,96 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 	"strings"
 
+	"quiz_go/internal/config"
 	"quiz_go/internal/ui"
 	"quiz_go/internal/quiz"
-
-	"github.com/AlecAivazis/survey/v2"
+	"quiz_go/internal/terminal"
 )
 
 
 func main() {
-	quiz := quiz.NewQuiz()
+	cfg, args, err := config.Carregar(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.Red("❌ "+err.Error()))
+		os.Exit(2)
+	}
+
+	if len(args) > 0 {
+		os.Exit(executarComando(cfg, args))
+	}
+
+	menuInterativo(cfg)
+}
+
+// menuInterativo mantém o fluxo original guiado pelo menu do terminal.
+func menuInterativo(cfg config.Config) {
+	base := cfg
+	if err := escolherPerfil(&cfg, false); err != nil {
+		fmt.Fprintln(os.Stderr, ui.Red("❌ "+err.Error()))
+		os.Exit(1)
+	}
+	q := quiz.NewQuiz(cfg)
 
 	for {
 		ui.MostrarTelaInicial()
 
-		modo := quiz.SelecionarModoJogo()
+		modo := terminal.SelecionarModoJogo(q)
+
+		// Depois das estatísticas, o modo escolhido passa pelas mesmas
+		// opções do menu.
+		jogar := true
+		for jogar && strings.Contains(modo, "estatísticas") {
+			q.MostrarEstatisticas()
+
+			if jogar = terminal.Confirmar("Deseja jogar agora?", true); jogar {
+				modo = terminal.SelecionarModoJogo(q)
+			}
+		}
+		if !jogar {
+			continue
+		}
 
 		if strings.Contains(modo, "Sair") {
 			break
 		}
 
-		if strings.Contains(modo, "estatísticas") {
-			quiz.MostrarEstatisticas()
-
-			var continuar bool
-			prompt := &survey.Confirm{
-				Message: "Deseja jogar agora?",
-				Default: true,
-			}
-			survey.AskOne(prompt, &continuar)
-
-			if !continuar {
-				continue
+		// As configurações do perfil anterior não passam para o novo.
+		if strings.Contains(modo, "Trocar de perfil") {
+			novo := base
+			if err := escolherPerfil(&novo, true); err == nil {
+				cfg = novo
+				q = quiz.NewQuiz(cfg)
 			}
+			continue
+		}
 
-			modo = quiz.SelecionarModoJogo()
+		if strings.Contains(modo, "Recordes") {
+			mostrarRecordes(q)
+			continue
 		}
 
-		questoesSelecionadas := quiz.FiltrarQuestoes(modo)
+		// A geração continua em segundo plano durante o quiz e é cancelada
+		// se o jogador sair antes do fim ou apertar Ctrl+C; as questões que
+		// faltam vêm do banco.
+		ctx, cancelar := signal.NotifyContext(context.Background(), os.Interrupt)
+		sessao := q.IniciarSessao(ctx, quiz.SelecaoDoMenu(modo))
 
-		if len(questoesSelecionadas) == 0 {
+		if sessao.Total() == 0 {
+			cancelar()
 			fmt.Println(ui.Red("❌ Nenhuma questão encontrada para este modo!"))
 			continue
 		}
 
-		quiz.ExecutarQuiz(questoesSelecionadas)
+		terminal.ExecutarQuiz(sessao)
+		cancelar()
 
-		if !quiz.JogarNovamente() {
+		if !terminal.JogarNovamente() {
 			break
 		}
 	}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"quiz_go/internal/config"
+	"quiz_go/internal/perfil"
+	"quiz_go/internal/stats"
+	"quiz_go/internal/terminal"
+	"quiz_go/internal/ui"
+)
+
+// errDesistiu indica que o jogador saiu do seletor de perfis com Ctrl+C.
+var errDesistiu = errors.New("nenhum perfil escolhido")
+
+// abrirPerfil aplica a cfg o perfil de --profile ou, sem ele, o escolhido
+// por perfil.Perfis.Escolher. Só o perfil padrão é criado se não existir.
+func abrirPerfil(cfg *config.Config) error {
+	perfis := perfil.Perfis{Dir: cfg.DiretorioDados}
+	nome := cfg.Perfil
+	if nome == "" {
+		var err error
+		if nome, err = perfis.Escolher(); err != nil {
+			return err
+		}
+	}
+
+	if !perfis.Existe(nome) {
+		if cfg.Perfil != "" {
+			return fmt.Errorf("%w: %s (crie com 'quiz_go profile create %s')", perfil.ErrNaoExiste, nome, nome)
+		}
+		if err := criarPerfil(perfis, nome, *cfg); err != nil {
+			return err
+		}
+	}
+	return usarPerfil(perfis, nome, cfg)
+}
+
+// escolherPerfil pergunta quem vai jogar, quando há mais de um perfil ou
+// sempre for verdadeiro, e aplica o perfil a cfg. Sem perfis, pede o nome
+// do primeiro.
+func escolherPerfil(cfg *config.Config, sempre bool) error {
+	if cfg.Perfil != "" && !sempre {
+		return abrirPerfil(cfg)
+	}
+
+	perfis := perfil.Perfis{Dir: cfg.DiretorioDados}
+	nomes, err := perfis.Listar()
+	if err != nil {
+		return err
+	}
+	atual := cfg.Perfil
+	if atual == "" {
+		atual = perfis.Ultimo()
+	}
+
+	var nome string
+	switch {
+	case len(nomes) == 0:
+		fmt.Println(ui.Cyan("👋 Bem-vindo! Cada jogador tem um perfil com as próprias estatísticas."))
+	case len(nomes) == 1 && !sempre:
+		nome = nomes[0]
+	default:
+		var novo bool
+		nome, novo = terminal.EscolherPerfil(nomes, atual)
+		if nome == "" && !novo {
+			return errDesistiu
+		}
+	}
+
+	if nome == "" {
+		if nome, err = perguntarNovoPerfil(perfis, *cfg); err != nil {
+			return err
+		}
+	}
+	return usarPerfil(perfis, nome, cfg)
+}
+
+// perguntarNovoPerfil pede o nome de um perfil até ele ser válido e o cria.
+func perguntarNovoPerfil(perfis perfil.Perfis, cfg config.Config) (string, error) {
+	for {
+		nome := terminal.Perguntar("Nome do novo perfil:")
+		if nome == "" {
+			return "", errDesistiu
+		}
+		err := criarPerfil(perfis, nome, cfg)
+		if err == nil {
+			return nome, nil
+		}
+		fmt.Println(ui.Red("❌ " + err.Error()))
+	}
+}
+
+// criarPerfil cria o perfil nome. O primeiro perfil recebe as estatísticas
+// de arquivo_stats, se ele ficou no lugar padrão de antes dos perfis.
+func criarPerfil(perfis perfil.Perfis, nome string, cfg config.Config) error {
+	existentes, err := perfis.Listar()
+	if err != nil {
+		return err
+	}
+	if err := perfis.Criar(nome); err != nil {
+		return err
+	}
+	fmt.Printf("%s Perfil %s criado.\n", ui.Green("👤"), ui.Bold(nome))
+
+	antigo := config.Padrao().ArquivoStats
+	if len(existentes) > 0 || cfg.ArquivoStats != antigo {
+		return nil
+	}
+	if _, err := os.Stat(antigo); err != nil {
+		return nil
+	}
+	if err := perfis.Importar(nome, antigo); err != nil {
+		return fmt.Errorf("não foi possível importar %s para o perfil %s: %v", antigo, nome, err)
+	}
+	fmt.Printf("%s Estatísticas de %s importadas para o perfil %s.\n", ui.Green("📥"), antigo, ui.Bold(nome))
+	return nil
+}
+
+func usarPerfil(perfis perfil.Perfis, nome string, cfg *config.Config) error {
+	if err := cfg.UsarPerfil(nome, perfis.ArquivoConfig(nome), perfis.ArquivoStats(nome)); err != nil {
+		return err
+	}
+	return perfis.MarcarUltimo(nome)
+}
+
+func comandoProfile(cfg config.Config, args []string) error {
+	perfis := perfil.Perfis{Dir: cfg.DiretorioDados}
+	if len(args) > 0 {
+		switch args[0] {
+		case "list":
+			return listarPerfis(perfis, args[1:])
+		case "create":
+			fs, err := argumentosPerfil("profile create", "<nome>", args[1:], 1)
+			if err != nil {
+				return err
+			}
+			return criarPerfil(perfis, fs.Arg(0), cfg)
+		case "rename":
+			fs, err := argumentosPerfil("profile rename", "<nome> <novo nome>", args[1:], 2)
+			if err != nil {
+				return err
+			}
+			if err := perfis.Renomear(fs.Arg(0), fs.Arg(1)); err != nil {
+				return err
+			}
+			fmt.Printf("%s Perfil %s renomeado para %s.\n", ui.Green("✏️"), fs.Arg(0), ui.Bold(fs.Arg(1)))
+			return nil
+		case "delete":
+			return removerPerfil(perfis, args[1:])
+		case "merge":
+			fs, err := argumentosPerfil("profile merge", "<origem> <destino>", args[1:], 2)
+			if err != nil {
+				return err
+			}
+			if err := perfis.Mesclar(fs.Arg(0), fs.Arg(1)); err != nil {
+				return err
+			}
+			fmt.Printf("%s Perfil %s mesclado em %s.\n", ui.Green("🔀"), fs.Arg(0), ui.Bold(fs.Arg(1)))
+			return nil
+		case "use":
+			fs, err := argumentosPerfil("profile use", "<nome>", args[1:], 1)
+			if err != nil {
+				return err
+			}
+			if !perfis.Existe(fs.Arg(0)) {
+				return fmt.Errorf("%w: %s", perfil.ErrNaoExiste, fs.Arg(0))
+			}
+			if err := perfis.MarcarUltimo(fs.Arg(0)); err != nil {
+				return err
+			}
+			fmt.Printf("%s Perfil %s em uso.\n", ui.Green("👤"), ui.Bold(fs.Arg(0)))
+			return nil
+		}
+	}
+	fmt.Fprintln(os.Stderr, "Uso: quiz_go profile list")
+	fmt.Fprintln(os.Stderr, "     quiz_go profile create <nome>")
+	fmt.Fprintln(os.Stderr, "     quiz_go profile rename <nome> <novo nome>")
+	fmt.Fprintln(os.Stderr, "     quiz_go profile delete [-yes] <nome>")
+	fmt.Fprintln(os.Stderr, "     quiz_go profile merge <origem> <destino>")
+	fmt.Fprintln(os.Stderr, "     quiz_go profile use <nome>")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Os perfis ficam em "+perfis.Dir+"; use --profile <nome> para jogar com um deles.")
+	return errUso
+}
+
+// argumentosPerfil lê as opções de um subcomando de profile, que espera n
+// argumentos.
+func argumentosPerfil(nome, argumentos string, args []string, n int) (*flag.FlagSet, error) {
+	fs := novoFlagSet(nome, argumentos)
+	if err := analisarFlags(fs, args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != n {
+		fs.Usage()
+		return nil, errUso
+	}
+	return fs, nil
+}
+
+func listarPerfis(perfis perfil.Perfis, args []string) error {
+	if _, err := argumentosPerfil("profile list", "", args, 0); err != nil {
+		return err
+	}
+	nomes, err := perfis.Listar()
+	if err != nil {
+		return err
+	}
+	if len(nomes) == 0 {
+		fmt.Println(ui.Yellow("👤 Nenhum perfil ainda. Crie um com 'quiz_go profile create <nome>'."))
+		return nil
+	}
+
+	ultimo := perfis.Ultimo()
+	for _, nome := range nomes {
+		marca := "  "
+		if nome == ultimo {
+			marca = ui.Green("▶ ")
+		}
+		resumo := "nenhum quiz ainda"
+		if s, err := stats.CarregarEstatisticas(perfis.ArquivoStats(nome)); err == nil && s.TotalQuizzes > 0 {
+			resumo = fmt.Sprintf("%d quizzes, %.1f%% de acertos, último em %s", s.TotalQuizzes, s.MediaPercentual, s.UltimoQuiz)
+		}
+		fmt.Printf("%s%-24s %s\n", marca, nome, ui.Blue(resumo))
+	}
+	return nil
+}
+
+func removerPerfil(perfis perfil.Perfis, args []string) error {
+	fs := novoFlagSet("profile delete", "<nome>")
+	sim := fs.Bool("yes", false, "remove sem pedir confirmação")
+	if err := analisarFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUso
+	}
+	nome := fs.Arg(0)
+	if !perfis.Existe(nome) {
+		return fmt.Errorf("%w: %s", perfil.ErrNaoExiste, nome)
+	}
+	if !*sim && !terminal.Confirmar(fmt.Sprintf("Remover o perfil %s, com as estatísticas e o histórico?", nome), false) {
+		return nil
+	}
+	if err := perfis.Remover(nome); err != nil {
+		return err
+	}
+	fmt.Printf("%s Perfil %s removido.\n", ui.Green("🗑️"), nome)
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+
+	"quiz_go/internal/config"
+	"quiz_go/internal/perfil"
+	"quiz_go/internal/quiz"
+	"quiz_go/internal/terminal"
+	"quiz_go/internal/ui"
+)
+
+// registrosPorQuadro é quantas partidas cada quadro de recordes mostra.
+const registrosPorQuadro = 10
+
+func comandoRecords(cfg config.Config, args []string) error {
+	fs := novoFlagSet("records", "")
+	modo := fs.String("mode", "", "mostra só o quadro deste modo: "+listarModos()+" (todos se vazio)")
+	dificuldade := fs.String("difficulty", "", "mostra só os quadros desta dificuldade: facil, medio ou dificil (todas se vazio)")
+	limite := fs.Int("n", registrosPorQuadro, "partidas por quadro (0 mostra todas)")
+	saida := fs.String("o", "", "exporta os quadros para um arquivo .csv ou .json em vez de mostrá-los")
+	if err := analisarFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *modo != "" {
+		if err := (quiz.Selecao{Modo: quiz.Modo(*modo)}).Validar(); err != nil {
+			return err
+		}
+	}
+	if err := (quiz.Selecao{Modo: quiz.ModoTodas, Dificuldade: *dificuldade}).Validar(); err != nil {
+		return err
+	}
+	if *limite < 0 {
+		return fmt.Errorf("quantidade inválida: %d", *limite)
+	}
+
+	partidas, err := perfil.Perfis{Dir: cfg.DiretorioDados}.Partidas()
+	if err != nil {
+		return err
+	}
+	quadros := filtrarQuadros(quiz.MontarQuadros(partidas, *limite), quiz.Modo(*modo), *dificuldade)
+
+	if *saida != "" {
+		return exportarRecordes(*saida, quadros)
+	}
+	terminal.MostrarRecordes(quadros)
+	return nil
+}
+
+// filtrarQuadros mantém os quadros do modo e da dificuldade; vazios aceitam
+// qualquer um.
+func filtrarQuadros(quadros []quiz.Quadro, modo quiz.Modo, dificuldade string) []quiz.Quadro {
+	var filtrados []quiz.Quadro
+	for _, q := range quadros {
+		if (modo == "" || q.Modo == modo) && (dificuldade == "" || q.Dificuldade == dificuldade) {
+			filtrados = append(filtrados, q)
+		}
+	}
+	return filtrados
+}
+
+func exportarRecordes(arquivo string, quadros []quiz.Quadro) error {
+	if err := quiz.SalvarQuadros(arquivo, quadros); err != nil {
+		return err
+	}
+	fmt.Printf("%s Recordes exportados para %s\n", ui.Green("💾"), arquivo)
+	return nil
+}
+
+// mostrarRecordes mostra os quadros no menu interativo e oferece exportá-los.
+func mostrarRecordes(q *quiz.Quiz) {
+	quadros, err := q.Quadros(registrosPorQuadro)
+	if err != nil {
+		fmt.Println(ui.Red("❌ " + err.Error()))
+		return
+	}
+	terminal.MostrarRecordes(quadros)
+	if len(quadros) == 0 || !terminal.Confirmar("Exportar os recordes para um arquivo?", false) {
+		return
+	}
+
+	arquivo := terminal.Perguntar("Arquivo (.csv ou .json):")
+	if arquivo == "" {
+		return
+	}
+	if err := exportarRecordes(arquivo, quadros); err != nil {
+		fmt.Println(ui.Red("❌ " + err.Error()))
+	}
+}
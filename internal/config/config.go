@@ -0,0 +1,482 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Origens possíveis de um valor de configuração, da menor para a maior precedência.
+const (
+	FontePadrao   = "padrão"
+	FonteArquivo  = "arquivo"
+	FontePerfil   = "perfil"
+	FonteAmbiente = "ambiente"
+	FonteFlag     = "flag"
+)
+
+// Quantidades guarda o número padrão de questões de cada modo de jogo.
+type Quantidades struct {
+	Todas           int
+	Rapido          int
+	Dificeis        int
+	Revisao         int
+	Adaptativo      int
+	PontosFracos    int
+	Prova           int
+	IAPersonalizado int
+	IAAvancado      int
+	IAExtremo       int
+}
+
+type Config struct {
+	Gerador                string
+	OllamaURL              string
+	OllamaModelo           string
+	OpenAIURL              string
+	OpenAIModelo           string
+	OpenAIChave            string
+	Temperatura            float64
+	OllamaSemente          int
+	TentativasGeracao      int
+	TimeoutConexao         time.Duration
+	TimeoutGeracao         time.Duration
+	IntervaloGeracao       time.Duration
+	TrabalhadoresGeracao   int
+	VerificacaoCodigo      string
+	TempoVerificacao       time.Duration
+	Autoverificacao        string
+	ModeloAutoverificacao  string
+	ArquivoAutoverificacao string
+	ArquivoStats           string
+	ArquivoGeradas         string
+	TempoQuestao           time.Duration
+	TempoProva             time.Duration
+	BonusVelocidade        float64
+	EnderecoServidor       string
+	DiretorioDados         string
+	Banco                  []string
+	Questoes               Quantidades
+
+	// Arquivo é o arquivo de configuração considerado, exista ele ou não.
+	Arquivo string
+
+	// Perfil é o perfil do jogador, escolhido com --profile ou QUIZ_PROFILE.
+	// Vazio se nenhum foi informado; veja UsarPerfil.
+	Perfil string
+
+	// ArquivoPerfil é o arquivo de configuração do perfil em uso.
+	ArquivoPerfil string
+
+	fontes map[string]string
+}
+
+// campo descreve uma chave de configuração e onde ela fica em Config.
+type campo struct {
+	chave     string
+	descricao string
+	destino   func(c *Config) any
+}
+
+var campos = []campo{
+	{"gerador", "gerador de questões: ollama, openai ou estatico", func(c *Config) any { return &c.Gerador }},
+	{"ollama_url", "endereço base do servidor Ollama", func(c *Config) any { return &c.OllamaURL }},
+	{"ollama_modelo", "modelo usado para gerar questões", func(c *Config) any { return &c.OllamaModelo }},
+	{"openai_url", "endereço do servidor compatível com OpenAI (LM Studio, vLLM, llama.cpp)", func(c *Config) any { return &c.OpenAIURL }},
+	{"openai_modelo", "modelo pedido ao servidor compatível com OpenAI", func(c *Config) any { return &c.OpenAIModelo }},
+	{"openai_chave", "chave de API enviada ao servidor compatível com OpenAI", func(c *Config) any { return &c.OpenAIChave }},
+	{"temperatura", "temperatura de amostragem usada na geração (0 a 2)", func(c *Config) any { return &c.Temperatura }},
+	{"ollama_semente", "semente do Ollama para gerar questões reproduzíveis", func(c *Config) any { return &c.OllamaSemente }},
+	{"tentativas_geracao", "tentativas por questão quando a resposta da IA é inválida", func(c *Config) any { return &c.TentativasGeracao }},
+	{"timeout_conexao", "tempo limite do teste de conexão com a IA", func(c *Config) any { return &c.TimeoutConexao }},
+	{"timeout_geracao", "tempo limite para gerar cada questão, incluindo as novas tentativas", func(c *Config) any { return &c.TimeoutGeracao }},
+	{"intervalo_geracao", "intervalo mínimo entre o início de duas requisições de geração (0 desativa)", func(c *Config) any { return &c.IntervaloGeracao }},
+	{"trabalhadores_geracao", "requisições de geração simultâneas", func(c *Config) any { return &c.TrabalhadoresGeracao }},
+	{"verificacao_codigo", "o que fazer quando a IA erra a saída de uma questão de código: corrigir, rejeitar ou desligada", func(c *Config) any { return &c.VerificacaoCodigo }},
+	{"tempo_verificacao", "tempo limite para compilar e executar o código de uma questão gerada", func(c *Config) any { return &c.TempoVerificacao }},
+	{"autoverificacao", "pedir a um modelo que responda às questões geradas e descartar as que ele errar: ligada ou desligada", func(c *Config) any { return &c.Autoverificacao }},
+	{"modelo_autoverificacao", "modelo que responde às questões na autoverificação (vazio usa o mesmo que gera)", func(c *Config) any { return &c.ModeloAutoverificacao }},
+	{"arquivo_autoverificacao", "arquivo onde a concordância de cada modelo na autoverificação é registrada", func(c *Config) any { return &c.ArquivoAutoverificacao }},
+	{"arquivo_stats", "arquivo onde as estatísticas são salvas", func(c *Config) any { return &c.ArquivoStats }},
+	{"arquivo_geradas", "arquivo onde as questões geradas pela IA são guardadas", func(c *Config) any { return &c.ArquivoGeradas }},
+	{"tempo_questao", "tempo limite para responder cada questão (0 desativa)", func(c *Config) any { return &c.TempoQuestao }},
+	{"tempo_prova", "duração total do modo 'Prova'", func(c *Config) any { return &c.TempoProva }},
+	{"bonus_velocidade", "pontos extras por acerto rápido, somados à pontuação (0 desativa)", func(c *Config) any { return &c.BonusVelocidade }},
+	{"endereco_servidor", "endereço em que o comando serve escuta, como localhost:7070", func(c *Config) any { return &c.EnderecoServidor }},
+	{"diretorio_dados", "diretório dos perfis dos jogadores, com as estatísticas e configurações de cada um", func(c *Config) any { return &c.DiretorioDados }},
+	{"banco", "arquivos ou diretórios extras de questões", func(c *Config) any { return &c.Banco }},
+	{"questoes_todas", "questões no modo 'Todas as questões'", func(c *Config) any { return &c.Questoes.Todas }},
+	{"questoes_rapido", "questões no modo 'Quiz rápido'", func(c *Config) any { return &c.Questoes.Rapido }},
+	{"questoes_dificeis", "questões no modo 'Apenas questões difíceis'", func(c *Config) any { return &c.Questoes.Dificeis }},
+	{"questoes_revisao", "máximo de questões no modo 'Revisão'", func(c *Config) any { return &c.Questoes.Revisao }},
+	{"questoes_adaptativo", "questões no modo 'Adaptativo'", func(c *Config) any { return &c.Questoes.Adaptativo }},
+	{"questoes_pontos_fracos", "questões no modo 'Foco nos pontos fracos'", func(c *Config) any { return &c.Questoes.PontosFracos }},
+	{"questoes_prova", "questões no modo 'Prova'", func(c *Config) any { return &c.Questoes.Prova }},
+	{"questoes_ia_personalizado", "questões no modo 'IA: Quiz personalizado'", func(c *Config) any { return &c.Questoes.IAPersonalizado }},
+	{"questoes_ia_avancado", "questões no modo 'IA: Questões avançadas'", func(c *Config) any { return &c.Questoes.IAAvancado }},
+	{"questoes_ia_extremo", "questões no modo 'IA: Desafio extremo'", func(c *Config) any { return &c.Questoes.IAExtremo }},
+}
+
+// aceitamZero são as durações em que 0 desativa o limite. Nas demais, 0
+// deixaria uma espera sem fim, como a conexão com a IA.
+var aceitamZero = map[string]bool{
+	"intervalo_geracao": true,
+	"tempo_questao":     true,
+}
+
+// Padrao retorna a configuração usada quando nada é informado.
+func Padrao() Config {
+	return Config{
+		Gerador:                "ollama",
+		OllamaURL:              "http://localhost:11434",
+		OllamaModelo:           "llama3:8b",
+		OpenAIURL:              "http://localhost:8080/v1",
+		OpenAIModelo:           "local-model",
+		Temperatura:            0.7,
+		TentativasGeracao:      3,
+		TimeoutConexao:         5 * time.Second,
+		TimeoutGeracao:         30 * time.Second,
+		IntervaloGeracao:       1 * time.Second,
+		TrabalhadoresGeracao:   3,
+		VerificacaoCodigo:      "corrigir",
+		TempoVerificacao:       20 * time.Second,
+		Autoverificacao:        "desligada",
+		ArquivoAutoverificacao: "quiz_autoverificacao.json",
+		ArquivoStats:           "quiz_stats.json",
+		ArquivoGeradas:         "quiz_questoes_geradas.json",
+		TempoProva:             20 * time.Minute,
+		EnderecoServidor:       "localhost:7070",
+		DiretorioDados:         DiretorioDadosPadrao(),
+		Questoes: Quantidades{
+			Todas:           10,
+			Rapido:          5,
+			Dificeis:        5,
+			Revisao:         20,
+			Adaptativo:      10,
+			PontosFracos:    10,
+			Prova:           20,
+			IAPersonalizado: 5,
+			IAAvancado:      3,
+			IAExtremo:       10,
+		},
+		fontes: make(map[string]string),
+	}
+}
+
+// ArquivoPadrao retorna o caminho do arquivo de configuração no diretório
+// de configuração do usuário ($XDG_CONFIG_HOME/quiz_go/config.yaml no Linux).
+func ArquivoPadrao() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "quiz_go", "config.yaml")
+}
+
+// DiretorioDadosPadrao retorna o diretório de dados do usuário para o quiz:
+// $XDG_DATA_HOME/quiz_go ou ~/.local/share/quiz_go no Linux, a pasta
+// Application Support no macOS e %LocalAppData% no Windows.
+func DiretorioDadosPadrao() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "quiz_go")
+	}
+	switch runtime.GOOS {
+	case "windows":
+		if dir := os.Getenv("LocalAppData"); dir != "" {
+			return filepath.Join(dir, "quiz_go")
+		}
+	case "darwin":
+		if dir, err := os.UserConfigDir(); err == nil {
+			return filepath.Join(dir, "quiz_go")
+		}
+	}
+	casa, err := os.UserHomeDir()
+	if err != nil {
+		return "quiz_go"
+	}
+	return filepath.Join(casa, ".local", "share", "quiz_go")
+}
+
+// Carregar monta a configuração efetiva aplicando, nesta ordem, os padrões,
+// o arquivo de configuração, as variáveis QUIZ_* e as flags globais em args.
+// Retorna os argumentos que sobraram depois das flags globais.
+func Carregar(args []string) (Config, []string, error) {
+	cfg := Padrao()
+
+	fs := flag.NewFlagSet("quiz_go", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	arquivo := fs.String("config", "", "arquivo de configuração")
+	perfil := fs.String("profile", "", "perfil do jogador")
+	valoresFlag := make(map[string]string)
+	for _, c := range campos {
+		chave := c.chave
+		fs.Func(NomeFlag(chave), c.descricao, func(v string) error {
+			valoresFlag[chave] = v
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return cfg, []string{"help"}, nil
+		}
+		return cfg, nil, fmt.Errorf("opção global inválida: %v", err)
+	}
+
+	cfg.Perfil = *perfil
+	if cfg.Perfil == "" {
+		cfg.Perfil = os.Getenv("QUIZ_PROFILE")
+	}
+
+	cfg.Arquivo = *arquivo
+	if cfg.Arquivo == "" {
+		cfg.Arquivo = os.Getenv("QUIZ_CONFIG")
+	}
+	explicito := cfg.Arquivo != ""
+	if !explicito {
+		cfg.Arquivo = ArquivoPadrao()
+	}
+
+	if cfg.Arquivo != "" {
+		if err := cfg.lerArquivo(cfg.Arquivo, FonteArquivo); err != nil {
+			if explicito || !errors.Is(err, os.ErrNotExist) {
+				return cfg, nil, err
+			}
+		}
+	}
+
+	for _, c := range campos {
+		if valor, ok := os.LookupEnv(NomeAmbiente(c.chave)); ok {
+			if err := cfg.definir(c, valor, FonteAmbiente); err != nil {
+				return cfg, nil, fmt.Errorf("%s: %v", NomeAmbiente(c.chave), err)
+			}
+		}
+	}
+
+	for _, c := range campos {
+		if valor, ok := valoresFlag[c.chave]; ok {
+			if err := cfg.definir(c, valor, FonteFlag); err != nil {
+				return cfg, nil, fmt.Errorf("--%s: %v", NomeFlag(c.chave), err)
+			}
+		}
+	}
+
+	return cfg, fs.Args(), nil
+}
+
+// NomeAmbiente retorna a variável de ambiente de uma chave, como QUIZ_OLLAMA_URL.
+func NomeAmbiente(chave string) string {
+	return "QUIZ_" + strings.ToUpper(chave)
+}
+
+// NomeFlag retorna a flag global de uma chave, como ollama-url.
+func NomeFlag(chave string) string {
+	return strings.ReplaceAll(chave, "_", "-")
+}
+
+// UsarPerfil aplica as configurações do perfil nome, lidas de arquivo, que
+// valem mais que as do arquivo de configuração e menos que as variáveis
+// QUIZ_* e as flags. Um arquivo inexistente é ignorado. Se arquivo_stats não
+// veio do perfil, do ambiente ou das flags, as estatísticas passam a ficar em
+// arquivoStats: um arquivo_stats do arquivo de configuração valeria para
+// todos os perfis.
+func (c *Config) UsarPerfil(nome, arquivo, arquivoStats string) error {
+	// As cópias de uma Config dividem o mapa; o perfil não muda as origens
+	// de quem foi copiado.
+	c.fontes = maps.Clone(c.fontes)
+	c.Perfil = nome
+	c.ArquivoPerfil = arquivo
+	if err := c.lerArquivo(arquivo, FontePerfil); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	switch c.fontes["arquivo_stats"] {
+	case FontePerfil, FonteAmbiente, FonteFlag:
+	default:
+		c.ArquivoStats = arquivoStats
+		if c.fontes == nil {
+			c.fontes = make(map[string]string)
+		}
+		c.fontes["arquivo_stats"] = FontePerfil
+	}
+	return nil
+}
+
+// lerArquivo aplica as chaves do arquivo YAML com a origem fonte. As chaves
+// de um perfil não substituem as que vieram do ambiente ou das flags.
+func (c *Config) lerArquivo(arquivo, fonte string) error {
+	data, err := os.ReadFile(arquivo)
+	if err != nil {
+		return fmt.Errorf("erro ao ler configuração: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: YAML inválido: %v", arquivo, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	raiz := doc.Content[0]
+	if raiz.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s:%d: esperado um mapa de chaves de configuração", arquivo, raiz.Line)
+	}
+
+	for i := 0; i+1 < len(raiz.Content); i += 2 {
+		chave, no := raiz.Content[i], raiz.Content[i+1]
+		def, ok := buscarCampo(chave.Value)
+		if !ok {
+			return fmt.Errorf("%s:%d: chave desconhecida '%s'", arquivo, chave.Line, chave.Value)
+		}
+
+		valor := no.Value
+		if no.Kind == yaml.SequenceNode {
+			var lista []string
+			if err := no.Decode(&lista); err != nil {
+				return fmt.Errorf("%s:%d: %v", arquivo, no.Line, err)
+			}
+			valor = strings.Join(lista, string(os.PathListSeparator))
+		}
+
+		if fonte == FontePerfil && (c.fontes[def.chave] == FonteAmbiente || c.fontes[def.chave] == FonteFlag) {
+			continue
+		}
+		if err := c.definir(def, valor, fonte); err != nil {
+			return fmt.Errorf("%s:%d: %s: %v", arquivo, no.Line, chave.Value, err)
+		}
+	}
+	return nil
+}
+
+func buscarCampo(chave string) (campo, bool) {
+	for _, c := range campos {
+		if c.chave == chave {
+			return c, true
+		}
+	}
+	return campo{}, false
+}
+
+func (c *Config) definir(def campo, valor, fonte string) error {
+	switch destino := def.destino(c).(type) {
+	case *string:
+		*destino = valor
+	case *int:
+		n, err := strconv.Atoi(strings.TrimSpace(valor))
+		if err != nil || n <= 0 {
+			return fmt.Errorf("esperado um número positivo, encontrado '%s'", valor)
+		}
+		*destino = n
+	case *float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(valor), 64)
+		if err != nil || f < 0 {
+			return fmt.Errorf("esperado um número não negativo, encontrado '%s'", valor)
+		}
+		*destino = f
+	case *time.Duration:
+		d, err := time.ParseDuration(strings.TrimSpace(valor))
+		if err != nil || d < 0 {
+			return fmt.Errorf("esperado uma duração como 5s ou 1m30s, encontrado '%s'", valor)
+		}
+		if d == 0 && !aceitamZero[def.chave] {
+			return fmt.Errorf("esperado uma duração maior que zero, encontrado '%s'", valor)
+		}
+		*destino = d
+	case *[]string:
+		*destino = filepath.SplitList(valor)
+	default:
+		return fmt.Errorf("tipo de configuração não suportado")
+	}
+
+	if c.fontes == nil {
+		c.fontes = make(map[string]string)
+	}
+	c.fontes[def.chave] = fonte
+	return nil
+}
+
+// Valor descreve o valor efetivo de uma chave e de onde ele veio.
+type Valor struct {
+	Chave     string
+	Valor     string
+	Fonte     string
+	Descricao string
+}
+
+// Valores lista todas as chaves com o valor efetivo e a origem de cada uma.
+func (c Config) Valores() []Valor {
+	valores := make([]Valor, 0, len(campos))
+	for _, def := range campos {
+		fonte := c.fontes[def.chave]
+		switch fonte {
+		case "":
+			fonte = FontePadrao
+		case FonteArquivo:
+			fonte = FonteArquivo + " " + c.Arquivo
+		case FontePerfil:
+			fonte = FontePerfil + " " + c.ArquivoPerfil
+		case FonteAmbiente:
+			fonte = FonteAmbiente + " " + NomeAmbiente(def.chave)
+		case FonteFlag:
+			fonte = FonteFlag + " --" + NomeFlag(def.chave)
+		}
+
+		var texto string
+		switch v := def.destino(&c).(type) {
+		case *string:
+			texto = *v
+		case *int:
+			texto = strconv.Itoa(*v)
+		case *float64:
+			texto = strconv.FormatFloat(*v, 'g', -1, 64)
+		case *time.Duration:
+			texto = v.String()
+		case *[]string:
+			texto = strings.Join(*v, string(os.PathListSeparator))
+		}
+		// Não exibir a chave de API em "config show".
+		if def.chave == "openai_chave" && texto != "" {
+			texto = "********"
+		}
+
+		valores = append(valores, Valor{Chave: def.chave, Valor: texto, Fonte: fonte, Descricao: def.descricao})
+	}
+	return valores
+}
+
+// QuantidadeModo retorna a quantidade configurada para um modo de jogo
+// (all, quick, hard, review, adaptive, weak, exam, ai-custom, ai-advanced ou ai-extreme), ou 0 se desconhecido.
+func (c Config) QuantidadeModo(modo string) int {
+	switch modo {
+	case "all":
+		return c.Questoes.Todas
+	case "quick":
+		return c.Questoes.Rapido
+	case "hard":
+		return c.Questoes.Dificeis
+	case "review":
+		return c.Questoes.Revisao
+	case "adaptive":
+		return c.Questoes.Adaptativo
+	case "weak":
+		return c.Questoes.PontosFracos
+	case "exam":
+		return c.Questoes.Prova
+	case "ai-custom":
+		return c.Questoes.IAPersonalizado
+	case "ai-advanced":
+		return c.Questoes.IAAvancado
+	case "ai-extreme":
+		return c.Questoes.IAExtremo
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// semArquivoGlobal faz Carregar ignorar o config.yaml e as variáveis QUIZ_*
+// de quem roda os testes.
+func semArquivoGlobal(t *testing.T) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("QUIZ_CONFIG", "")
+	t.Setenv("QUIZ_PROFILE", "")
+}
+
+func TestDuracoesZero(t *testing.T) {
+	semArquivoGlobal(t)
+
+	for _, chave := range []string{"timeout-conexao", "timeout-geracao", "tempo-verificacao", "tempo-prova"} {
+		if _, _, err := Carregar([]string{"--" + chave, "0s"}); err == nil {
+			t.Errorf("--%s 0s deveria ser recusado", chave)
+		}
+	}
+
+	cfg, _, err := Carregar([]string{"--tempo-questao", "0", "--intervalo-geracao", "0s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TempoQuestao != 0 || cfg.IntervaloGeracao != 0 {
+		t.Errorf("tempo_questao %v e intervalo_geracao %v, esperado 0 (desativados)", cfg.TempoQuestao, cfg.IntervaloGeracao)
+	}
+
+	if cfg, _, err := Carregar([]string{"--timeout-conexao", "2s"}); err != nil || cfg.TimeoutConexao != 2*time.Second {
+		t.Errorf("timeout_conexao = %v, %v", cfg.TimeoutConexao, err)
+	}
+}
+
+func TestUsarPerfilSeparaAsEstatisticas(t *testing.T) {
+	semArquivoGlobal(t)
+	dir := t.TempDir()
+	global := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(global, []byte("arquivo_stats: "+filepath.Join(dir, "stats.json")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("QUIZ_CONFIG", global)
+
+	base, _, err := Carregar(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, nome := range []string{"Ana", "Bia"} {
+		cfg := base
+		esperado := filepath.Join(dir, nome, "stats.json")
+		if err := cfg.UsarPerfil(nome, filepath.Join(dir, nome, "config.yaml"), esperado); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.ArquivoStats != esperado {
+			t.Errorf("%s: arquivo_stats = %s, esperado %s", nome, cfg.ArquivoStats, esperado)
+		}
+	}
+
+	// Informado no ambiente, continua valendo para o perfil.
+	t.Setenv("QUIZ_ARQUIVO_STATS", filepath.Join(dir, "ambiente.json"))
+	cfg, _, err := Carregar(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.UsarPerfil("Ana", filepath.Join(dir, "Ana", "config.yaml"), filepath.Join(dir, "Ana", "stats.json")); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ArquivoStats != filepath.Join(dir, "ambiente.json") {
+		t.Errorf("arquivo_stats = %s, esperado o do ambiente", cfg.ArquivoStats)
+	}
+}
@@ -0,0 +1,234 @@
+// Package ollamafalso oferece um servidor Ollama falso, baseado em
+// httptest, para testar a geração de questões sem rede e sem modelo.
+//
+// O servidor atende /api/generate com uma fila de respostas, uma por
+// requisição e na ordem em que foram dadas; uma resposta de Para espera uma
+// requisição com o trecho no prompt, para testar requisições simultâneas,
+// que chegam em qualquer ordem. A verificação de conexão feita
+// pelo quiz (o prompt "test") é sempre respondida com sucesso, sem consumir
+// a fila. Quando a fila acaba, o servidor responde com status 500.
+//
+// A mesma fila atende /v1/chat/completions, da API compatível com OpenAI,
+// com o texto de cada resposta na mensagem da primeira escolha; /v1/models
+// responde sempre com sucesso.
+package ollamafalso
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// PromptConexao é o prompt que o quiz envia para saber se o Ollama responde.
+const PromptConexao = "test"
+
+// Resposta descreve como o servidor responde a uma requisição.
+type Resposta struct {
+	Texto  string        // campo "response" da resposta do Ollama
+	Corpo  string        // corpo bruto; se preenchido, substitui o JSON montado com Texto
+	Status int           // status HTTP; zero é 200
+	Atraso time.Duration // espera antes de responder, interrompida se o cliente desistir
+	Trecho string        // se preenchido, só atende um prompt que contenha este texto
+}
+
+// Requisicao é uma requisição recebida em /api/generate. Nas de
+// /v1/chat/completions, Prompt é a última mensagem e Formato, o
+// response_format.
+type Requisicao struct {
+	Modelo  string          `json:"model"`
+	Prompt  string          `json:"prompt"`
+	Formato json.RawMessage `json:"format"`
+	Chegada time.Time       `json:"-"`
+}
+
+// requisicaoChat é uma requisição recebida em /v1/chat/completions.
+type requisicaoChat struct {
+	Modelo    string `json:"model"`
+	Mensagens []struct {
+		Conteudo string `json:"content"`
+	} `json:"messages"`
+	Formato json.RawMessage `json:"response_format"`
+}
+
+// Servidor é o Ollama falso. URL é o endereço base a passar ao quiz.
+type Servidor struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	respostas   []Resposta
+	requisicoes []Requisicao
+}
+
+// Novo inicia um servidor que responde, em ordem, com as respostas dadas. O
+// servidor é encerrado no fim do teste.
+func Novo(t testing.TB, respostas ...Resposta) *Servidor {
+	t.Helper()
+	s := &Servidor{respostas: respostas}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.atender))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Adicionar põe mais respostas no fim da fila.
+func (s *Servidor) Adicionar(respostas ...Resposta) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.respostas = append(s.respostas, respostas...)
+}
+
+// Requisicoes retorna as requisições de geração recebidas até agora, sem as
+// verificações de conexão.
+func (s *Servidor) Requisicoes() []Requisicao {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Requisicao(nil), s.requisicoes...)
+}
+
+// Pendentes conta as respostas da fila ainda não usadas.
+func (s *Servidor) Pendentes() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.respostas)
+}
+
+func (s *Servidor) atender(w http.ResponseWriter, r *http.Request) {
+	var req Requisicao
+	chat := false
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/generate":
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("requisição inválida: %v", err), http.StatusBadRequest)
+			return
+		}
+		if req.Prompt == PromptConexao {
+			escrever(w, Resposta{Texto: "ok"}, false)
+			return
+		}
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/chat/completions":
+		var rc requisicaoChat
+		if err := json.NewDecoder(r.Body).Decode(&rc); err != nil || len(rc.Mensagens) == 0 {
+			http.Error(w, fmt.Sprintf("requisição inválida: %v", err), http.StatusBadRequest)
+			return
+		}
+		req = Requisicao{Modelo: rc.Modelo, Prompt: rc.Mensagens[len(rc.Mensagens)-1].Conteudo, Formato: rc.Formato}
+		chat = true
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/models":
+		fmt.Fprint(w, `{"data": []}`)
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	req.Chegada = time.Now()
+	s.mu.Lock()
+	s.requisicoes = append(s.requisicoes, req)
+	if len(s.respostas) == 0 {
+		s.mu.Unlock()
+		http.Error(w, "nenhuma resposta gravada para esta requisição", http.StatusInternalServerError)
+		return
+	}
+	i := slices.IndexFunc(s.respostas, func(r Resposta) bool {
+		return strings.Contains(req.Prompt, r.Trecho)
+	})
+	if i < 0 {
+		s.mu.Unlock()
+		http.Error(w, "nenhuma resposta gravada para este prompt", http.StatusInternalServerError)
+		return
+	}
+	resposta := s.respostas[i]
+	s.respostas = slices.Delete(s.respostas, i, i+1)
+	s.mu.Unlock()
+
+	if resposta.Atraso > 0 {
+		select {
+		case <-time.After(resposta.Atraso):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	escrever(w, resposta, chat)
+}
+
+func escrever(w http.ResponseWriter, resposta Resposta, chat bool) {
+	corpo := resposta.Corpo
+	if corpo == "" {
+		var v any = map[string]any{"response": resposta.Texto, "done": true}
+		if chat {
+			mensagem := map[string]any{"role": "assistant", "content": resposta.Texto}
+			v = map[string]any{"choices": []any{map[string]any{"message": mensagem}}}
+		}
+		data, _ := json.Marshal(v)
+		corpo = string(data)
+	}
+	status := resposta.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprint(w, corpo)
+}
+
+// JSON responde com v serializado no campo "response", como o modelo faz
+// com a saída estruturada. Serve para questões e respostas da
+// autoverificação.
+func JSON(v any) Resposta {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("ollamafalso: %v", err))
+	}
+	return Resposta{Texto: string(data)}
+}
+
+// Erro responde com o status HTTP e a mensagem dados.
+func Erro(status int, mensagem string) Resposta {
+	return Resposta{Status: status, Corpo: mensagem}
+}
+
+// Malformado responde com um corpo que não é a resposta JSON do Ollama.
+func Malformado(corpo string) Resposta {
+	return Resposta{Corpo: corpo}
+}
+
+// Lento atrasa a resposta r.
+func Lento(atraso time.Duration, r Resposta) Resposta {
+	r.Atraso = atraso
+	return r
+}
+
+// Para reserva a resposta r para a requisição cujo prompt contém trecho.
+func Para(trecho string, r Resposta) Resposta {
+	r.Trecho = trecho
+	return r
+}
+
+// Gravacao lê respostas gravadas de um Ollama real: um arquivo JSON com a
+// lista dos corpos devolvidos por /api/generate, como
+// [{"response": "...", "done": true}, ...]. Cada corpo é repetido como foi
+// gravado.
+func Gravacao(t testing.TB, arquivo string) []Resposta {
+	t.Helper()
+	data, err := os.ReadFile(arquivo)
+	if err != nil {
+		t.Fatalf("erro ao ler gravação: %v", err)
+	}
+
+	var corpos []json.RawMessage
+	if err := json.Unmarshal(data, &corpos); err != nil {
+		t.Fatalf("%s: JSON inválido: %v", arquivo, err)
+	}
+
+	respostas := make([]Resposta, len(corpos))
+	for i, corpo := range corpos {
+		respostas[i] = Resposta{Corpo: string(corpo)}
+	}
+	return respostas
+}
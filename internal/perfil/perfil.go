@@ -0,0 +1,277 @@
+// Package perfil guarda os perfis dos jogadores que dividem a máquina. Cada
+// perfil é um diretório em <dados>/perfis, com as estatísticas (que incluem
+// o histórico de respostas) e um config.yaml opcional com as configurações
+// só daquele jogador.
+package perfil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"quiz_go/internal/stats"
+)
+
+// Padrao é o perfil usado quando nenhum foi escolhido e ainda não há outros.
+const Padrao = "jogador"
+
+const (
+	arquivoStats  = "stats.json"
+	arquivoConfig = "config.yaml"
+	arquivoUltimo = "ultimo_perfil"
+
+	// tamanhoNome limita o nome dos perfis, em caracteres.
+	tamanhoNome = 32
+)
+
+var (
+	ErrNaoExiste = errors.New("perfil não encontrado")
+	ErrJaExiste  = errors.New("já existe um perfil com esse nome")
+)
+
+// Perfis são os perfis guardados no diretório de dados Dir.
+type Perfis struct {
+	Dir string
+}
+
+// dir retorna o diretório do perfil nome.
+func (p Perfis) dir(nome string) string {
+	return filepath.Join(p.Dir, "perfis", nome)
+}
+
+// ArquivoStats retorna o arquivo de estatísticas do perfil nome.
+func (p Perfis) ArquivoStats(nome string) string {
+	return filepath.Join(p.dir(nome), arquivoStats)
+}
+
+// ArquivoConfig retorna o arquivo de configuração do perfil nome.
+func (p Perfis) ArquivoConfig(nome string) string {
+	return filepath.Join(p.dir(nome), arquivoConfig)
+}
+
+// Listar retorna os nomes dos perfis em ordem alfabética.
+func (p Perfis) Listar() ([]string, error) {
+	entradas, err := os.ReadDir(filepath.Join(p.Dir, "perfis"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var nomes []string
+	for _, e := range entradas {
+		if e.IsDir() && ValidarNome(e.Name()) == nil {
+			nomes = append(nomes, e.Name())
+		}
+	}
+	return nomes, nil
+}
+
+// Existe informa se o perfil nome existe.
+func (p Perfis) Existe(nome string) bool {
+	if ValidarNome(nome) != nil {
+		return false
+	}
+	info, err := os.Stat(p.dir(nome))
+	return err == nil && info.IsDir()
+}
+
+// ValidarNome confere se nome serve como nome de perfil: não vazio, com até
+// 32 caracteres, sem separadores de caminho e sem começar por ponto.
+func ValidarNome(nome string) error {
+	switch {
+	case strings.TrimSpace(nome) == "":
+		return errors.New("informe o nome do perfil")
+	case nome != strings.TrimSpace(nome):
+		return fmt.Errorf("o nome do perfil '%s' não pode começar ou terminar com espaços", nome)
+	case utf8.RuneCountInString(nome) > tamanhoNome:
+		return fmt.Errorf("o nome do perfil pode ter até %d caracteres", tamanhoNome)
+	case strings.HasPrefix(nome, "."):
+		return fmt.Errorf("o nome do perfil '%s' não pode começar com ponto", nome)
+	case strings.ContainsFunc(nome, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(" ._-", r)
+	}):
+		return fmt.Errorf("o nome do perfil '%s' só pode ter letras, números, espaços, '.', '_' e '-'", nome)
+	}
+	return nil
+}
+
+// Criar cria o perfil nome, sem estatísticas.
+func (p Perfis) Criar(nome string) error {
+	if err := ValidarNome(nome); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(p.Dir, "perfis"), 0755); err != nil {
+		return err
+	}
+	if err := os.Mkdir(p.dir(nome), 0755); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("%w: %s", ErrJaExiste, nome)
+		}
+		return err
+	}
+	return nil
+}
+
+// Importar copia para o perfil nome as estatísticas do arquivo, como o
+// quiz_stats.json de antes dos perfis. O perfil não pode ter estatísticas.
+func (p Perfis) Importar(nome, arquivo string) error {
+	if !p.Existe(nome) {
+		return fmt.Errorf("%w: %s", ErrNaoExiste, nome)
+	}
+	origem, err := os.Open(arquivo)
+	if err != nil {
+		return err
+	}
+	defer origem.Close()
+
+	destino, err := os.OpenFile(p.ArquivoStats(nome), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(destino, origem); err != nil {
+		destino.Close()
+		return err
+	}
+	return destino.Close()
+}
+
+// Renomear troca o nome do perfil de para para.
+func (p Perfis) Renomear(de, para string) error {
+	if !p.Existe(de) {
+		return fmt.Errorf("%w: %s", ErrNaoExiste, de)
+	}
+	if err := ValidarNome(para); err != nil {
+		return err
+	}
+	if p.Existe(para) {
+		return fmt.Errorf("%w: %s", ErrJaExiste, para)
+	}
+	ultimo := p.Ultimo()
+	if err := os.Rename(p.dir(de), p.dir(para)); err != nil {
+		return err
+	}
+	if ultimo == de {
+		return p.MarcarUltimo(para)
+	}
+	return nil
+}
+
+// Remover apaga o perfil nome, com as estatísticas e as configurações.
+func (p Perfis) Remover(nome string) error {
+	if !p.Existe(nome) {
+		return fmt.Errorf("%w: %s", ErrNaoExiste, nome)
+	}
+	ultimo := p.Ultimo()
+	if err := os.RemoveAll(p.dir(nome)); err != nil {
+		return err
+	}
+	if ultimo == nome {
+		os.Remove(filepath.Join(p.Dir, arquivoUltimo))
+	}
+	return nil
+}
+
+// Mesclar junta as estatísticas do perfil origem às do destino (veja
+// stats.Mesclar) e apaga a origem. As configurações do destino ficam; as da
+// origem só são mantidas se o destino não tiver nenhuma. Se as estatísticas
+// de um dos dois não puderem ser lidas, nada é alterado.
+func (p Perfis) Mesclar(origem, destino string) error {
+	for _, nome := range []string{origem, destino} {
+		if !p.Existe(nome) {
+			return fmt.Errorf("%w: %s", ErrNaoExiste, nome)
+		}
+	}
+	if origem == destino {
+		return errors.New("escolha dois perfis diferentes para mesclar")
+	}
+
+	a, err := p.carregarStats(destino)
+	if err != nil {
+		return err
+	}
+	b, err := p.carregarStats(origem)
+	if err != nil {
+		return err
+	}
+	if err := stats.SalvarEstatisticas(p.ArquivoStats(destino), stats.Mesclar(a, b)); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(p.ArquivoConfig(destino)); errors.Is(err, os.ErrNotExist) {
+		if err := os.Rename(p.ArquivoConfig(origem), p.ArquivoConfig(destino)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return p.Remover(origem)
+}
+
+// carregarStats lê as estatísticas do perfil; um perfil que ainda não jogou
+// tem estatísticas zeradas.
+func (p Perfis) carregarStats(nome string) (stats.Estatisticas, error) {
+	s, err := stats.CarregarEstatisticas(p.ArquivoStats(nome))
+	if errors.Is(err, os.ErrNotExist) {
+		return stats.Estatisticas{}, nil
+	}
+	return s, err
+}
+
+// Partidas retorna as partidas terminadas de cada perfil, para os quadros de
+// recordes. Perfis sem estatísticas legíveis ficam de fora.
+func (p Perfis) Partidas() (map[string][]stats.Partida, error) {
+	nomes, err := p.Listar()
+	if err != nil {
+		return nil, err
+	}
+	partidas := make(map[string][]stats.Partida, len(nomes))
+	for _, nome := range nomes {
+		if s, err := stats.CarregarEstatisticas(p.ArquivoStats(nome)); err == nil {
+			partidas[nome] = s.Partidas
+		}
+	}
+	return partidas, nil
+}
+
+// Ultimo retorna o último perfil usado, ou "" se ele não existe mais.
+func (p Perfis) Ultimo() string {
+	dados, err := os.ReadFile(filepath.Join(p.Dir, arquivoUltimo))
+	if err != nil {
+		return ""
+	}
+	nome := strings.TrimSpace(string(dados))
+	if !p.Existe(nome) {
+		return ""
+	}
+	return nome
+}
+
+// MarcarUltimo registra nome como o último perfil usado.
+func (p Perfis) MarcarUltimo(nome string) error {
+	return os.WriteFile(filepath.Join(p.Dir, arquivoUltimo), []byte(nome+"\n"), 0644)
+}
+
+// Escolher retorna o perfil a usar quando nenhum foi informado: o último
+// usado, o único existente ou Padrao, se ele existe ou ainda não há perfis.
+// Com vários perfis e nenhum desses, o jogador precisa escolher.
+func (p Perfis) Escolher() (string, error) {
+	if ultimo := p.Ultimo(); ultimo != "" {
+		return ultimo, nil
+	}
+	nomes, err := p.Listar()
+	if err != nil {
+		return "", err
+	}
+	if len(nomes) == 1 {
+		return nomes[0], nil
+	}
+	if slices.Contains(nomes, Padrao) || len(nomes) == 0 {
+		return Padrao, nil
+	}
+	return "", fmt.Errorf("há %d perfis; escolha um com --profile (%s)", len(nomes), strings.Join(nomes, ", "))
+}
@@ -0,0 +1,151 @@
+package perfil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"quiz_go/internal/stats"
+)
+
+func TestCriarRenomearRemover(t *testing.T) {
+	p := Perfis{Dir: t.TempDir()}
+
+	for _, nome := range []string{"", " Ana", "../fora", ".oculto", "a/b"} {
+		if err := p.Criar(nome); err == nil {
+			t.Errorf("o nome %q deveria ser recusado", nome)
+		}
+	}
+
+	for _, nome := range []string{"Bia", "Ana", "João Pedro"} {
+		if err := p.Criar(nome); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := p.Criar("Ana"); !errors.Is(err, ErrJaExiste) {
+		t.Errorf("perfil repetido: %v", err)
+	}
+	if nomes, _ := p.Listar(); fmt.Sprint(nomes) != "[Ana Bia João Pedro]" {
+		t.Errorf("perfis = %v", nomes)
+	}
+
+	p.MarcarUltimo("Ana")
+	if err := p.Renomear("Ana", "Bia"); !errors.Is(err, ErrJaExiste) {
+		t.Errorf("renomear para um nome em uso: %v", err)
+	}
+	if err := p.Renomear("Ana", "Aninha"); err != nil {
+		t.Fatal(err)
+	}
+	if p.Existe("Ana") || !p.Existe("Aninha") || p.Ultimo() != "Aninha" {
+		t.Errorf("depois de renomear: Ana %v, Aninha %v, último %q", p.Existe("Ana"), p.Existe("Aninha"), p.Ultimo())
+	}
+
+	if err := p.Remover("Aninha"); err != nil {
+		t.Fatal(err)
+	}
+	if p.Existe("Aninha") || p.Ultimo() != "" {
+		t.Error("o perfil removido deveria sumir, inclusive como último usado")
+	}
+	if err := p.Remover("Aninha"); !errors.Is(err, ErrNaoExiste) {
+		t.Errorf("remover de novo: %v", err)
+	}
+}
+
+func TestEscolher(t *testing.T) {
+	p := Perfis{Dir: t.TempDir()}
+
+	if nome, err := p.Escolher(); nome != Padrao || err != nil {
+		t.Errorf("sem perfis: %q, %v", nome, err)
+	}
+	p.Criar("Ana")
+	if nome, _ := p.Escolher(); nome != "Ana" {
+		t.Errorf("com um perfil: %q", nome)
+	}
+	p.Criar("Bia")
+	if _, err := p.Escolher(); err == nil {
+		t.Error("com vários perfis e nenhum usado, o jogador deveria escolher")
+	}
+	p.MarcarUltimo("Bia")
+	if nome, _ := p.Escolher(); nome != "Bia" {
+		t.Errorf("com um último usado: %q", nome)
+	}
+}
+
+func TestMesclar(t *testing.T) {
+	p := Perfis{Dir: t.TempDir()}
+	p.Criar("Ana")
+	p.Criar("Bia")
+
+	inicio := time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+	resposta := func(id int, minutos int, correta bool) stats.Resposta {
+		return stats.Resposta{QuestaoID: id, Dificuldade: "facil", Correta: correta, Sessao: "s", Momento: inicio.Add(time.Duration(minutos) * time.Minute)}
+	}
+	comum := resposta(1, 0, true)
+
+	var ana, bia stats.Estatisticas
+	ana.Registrar(comum, resposta(2, 5, false))
+	ana.TotalQuizzes, ana.TotalAcertos, ana.TotalQuestoes, ana.MelhorScore = 1, 1, 2, 1
+	ana.UltimoQuiz = "02/03/2026 10:05"
+	bia.Registrar(comum, resposta(3, 2, true))
+	bia.TotalQuizzes, bia.TotalAcertos, bia.TotalQuestoes, bia.MelhorScore = 1, 2, 2, 2
+	bia.UltimoQuiz = "01/03/2026 09:00"
+	stats.SalvarEstatisticas(p.ArquivoStats("Ana"), ana)
+	stats.SalvarEstatisticas(p.ArquivoStats("Bia"), bia)
+	os.WriteFile(p.ArquivoConfig("Bia"), []byte("tempo_questao: 30s\n"), 0644)
+
+	if err := p.Mesclar("Bia", "Ana"); err != nil {
+		t.Fatal(err)
+	}
+	if p.Existe("Bia") {
+		t.Error("a origem deveria ser removida")
+	}
+	if _, err := os.Stat(p.ArquivoConfig("Ana")); err != nil {
+		t.Error("sem configurações no destino, as da origem deveriam ser mantidas")
+	}
+
+	m, err := stats.CarregarEstatisticas(p.ArquivoStats("Ana"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.TotalQuizzes != 2 || m.TotalAcertos != 3 || m.TotalQuestoes != 4 || m.MelhorScore != 2 || m.MediaPercentual != 75 {
+		t.Errorf("totais = %+v", m)
+	}
+	if m.UltimoQuiz != "02/03/2026 10:05" {
+		t.Errorf("último quiz = %q", m.UltimoQuiz)
+	}
+	// A resposta que estava nos dois perfis fica uma vez, e o histórico
+	// fica em ordem.
+	var ids []int
+	for _, r := range m.Respostas {
+		ids = append(ids, r.QuestaoID)
+	}
+	if fmt.Sprint(ids) != "[1 3 2]" {
+		t.Errorf("respostas = %v", ids)
+	}
+	if len(m.Revisoes) != 3 || m.Habilidade == 0 {
+		t.Errorf("revisões e habilidade deveriam ser recalculadas: %d revisões, habilidade %v", len(m.Revisoes), m.Habilidade)
+	}
+
+	if err := p.Mesclar("Ana", "Ana"); err == nil {
+		t.Error("mesclar um perfil nele mesmo deveria falhar")
+	}
+
+	// Estatísticas truncadas não são mescladas como zeradas, e a origem fica.
+	p.Criar("Caio")
+	truncado := []byte(`{"total_quizzes": 7, "total_acertos": 3`)
+	os.WriteFile(p.ArquivoStats("Caio"), truncado, 0644)
+	if err := p.Mesclar("Caio", "Ana"); err == nil {
+		t.Error("mesclar uma origem com estatísticas truncadas deveria falhar")
+	}
+	if !p.Existe("Caio") {
+		t.Error("a origem com estatísticas truncadas não deveria ser removida")
+	}
+	if err := p.Mesclar("Ana", "Caio"); err == nil || !p.Existe("Ana") {
+		t.Errorf("mesclar em um destino com estatísticas truncadas deveria falhar sem remover a origem: %v", err)
+	}
+	if m, err := stats.CarregarEstatisticas(p.ArquivoStats("Ana")); err != nil || m.TotalQuizzes != 2 {
+		t.Errorf("o destino não deveria mudar: %d quizzes, %v", m.TotalQuizzes, err)
+	}
+}
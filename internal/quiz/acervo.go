@@ -0,0 +1,178 @@
+package quiz
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PrimeiroIDGerado é o menor ID dado às questões geradas por IA. Os IDs
+// vêm do conteúdo da questão e ficam acima dos bancos locais e do pacote.
+const PrimeiroIDGerado = 1_000_000_000
+
+// QuestaoGuardada é uma questão gerada por IA com os dados de origem.
+type QuestaoGuardada struct {
+	Questao
+	Modelo       string    `json:"modelo"`
+	VersaoPrompt int       `json:"versao_prompt"`
+	CriadaEm     time.Time `json:"criada_em"`
+}
+
+// AcervoGerado guarda em disco as questões geradas por IA para que sejam
+// reaproveitadas quando a IA não estiver disponível. O arquivo usa o mesmo
+// formato do banco ({"questoes": [...]}), então também pode ser importado
+// ou passado em "banco". É seguro para uso concorrente.
+type AcervoGerado struct {
+	arquivo string
+
+	mu       sync.Mutex
+	questoes []QuestaoGuardada
+	textos   map[string]bool
+	alterado bool
+}
+
+// AbrirAcervo lê o acervo de arquivo. Um arquivo inexistente resulta num
+// acervo vazio, criado no primeiro Salvar.
+func AbrirAcervo(arquivo string) (*AcervoGerado, error) {
+	a := &AcervoGerado{arquivo: arquivo, textos: make(map[string]bool)}
+
+	data, err := os.ReadFile(arquivo)
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler questões geradas: %v", err)
+	}
+
+	var conteudo struct {
+		Questoes []QuestaoGuardada `json:"questoes"`
+	}
+	if err := json.Unmarshal(data, &conteudo); err != nil {
+		return nil, fmt.Errorf("%s: JSON inválido: %v", arquivo, err)
+	}
+
+	for _, questao := range conteudo.Questoes {
+		texto := textoNormalizado(questao.Questao)
+		if a.textos[texto] {
+			continue
+		}
+		a.textos[texto] = true
+		a.questoes = append(a.questoes, questao)
+	}
+	return a, nil
+}
+
+// Adicionar guarda uma questão gerada pelo modelo informado. Retorna false
+// se já existe uma questão com o mesmo texto normalizado ou se ela não é
+// válida como registro do banco.
+func (a *AcervoGerado) Adicionar(questao Questao, modelo string) bool {
+	questao.ID = IDConteudo(questao)
+	if err := validarRegistro(&questao); err != nil {
+		return false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	texto := textoNormalizado(questao)
+	if a.textos[texto] {
+		return false
+	}
+	a.textos[texto] = true
+	a.questoes = append(a.questoes, QuestaoGuardada{
+		Questao:      questao,
+		Modelo:       modelo,
+		VersaoPrompt: VersaoPrompt,
+		CriadaEm:     time.Now().UTC(),
+	})
+	a.alterado = true
+	return true
+}
+
+// Questoes retorna uma cópia das questões guardadas.
+func (a *AcervoGerado) Questoes() []Questao {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	questoes := make([]Questao, len(a.questoes))
+	for i, guardada := range a.questoes {
+		questoes[i] = guardada.Questao
+	}
+	return questoes
+}
+
+// Salvar grava o acervo se houve questões novas desde a leitura. A escrita
+// passa por um arquivo temporário para não corromper o acervo existente.
+func (a *AcervoGerado) Salvar() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.alterado {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(struct {
+		Questoes []QuestaoGuardada `json:"questoes"`
+	}{a.questoes}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar questões geradas: %v", err)
+	}
+
+	if dir := filepath.Dir(a.arquivo); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("erro ao criar diretório %s: %v", dir, err)
+		}
+	}
+
+	tmp := a.arquivo + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("erro ao salvar questões geradas: %v", err)
+	}
+	if err := os.Rename(tmp, a.arquivo); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("erro ao salvar questões geradas: %v", err)
+	}
+
+	a.alterado = false
+	return nil
+}
+
+// textoNormalizado junta o enunciado e as opções em minúsculas, sem acentos,
+// com espaços colapsados e as opções em ordem alfabética. Questões que só
+// diferem em caixa, acentos, espaços, pontuação final ou ordem das opções
+// ficam iguais. Símbolos
+// no meio do texto são mantidos: em Go, ":=" e "=" são respostas diferentes.
+// O código, se houver, entra com os espaços colapsados.
+func textoNormalizado(questao Questao) string {
+	opcoes := make([]string, len(questao.Opcoes))
+	for i, opcao := range questao.Opcoes {
+		opcoes[i] = normalizar(semAcentos(opcao))
+	}
+	sort.Strings(opcoes)
+	texto := normalizar(semAcentos(questao.Questao)) + "|" + strings.Join(opcoes, "|")
+	if questao.Codigo != "" {
+		// O enunciado das questões com código costuma se repetir.
+		texto += "|" + strings.Join(strings.Fields(questao.Codigo), " ")
+	}
+	return texto
+}
+
+func normalizar(texto string) string {
+	texto = strings.Join(strings.Fields(strings.ToLower(texto)), " ")
+	return strings.TrimRight(texto, ".?!;: ")
+}
+
+// IDConteudo calcula um ID estável a partir do texto normalizado da
+// questão, entre PrimeiroIDGerado e 2*PrimeiroIDGerado-1.
+func IDConteudo(questao Questao) int {
+	soma := sha256.Sum256([]byte(textoNormalizado(questao)))
+	return PrimeiroIDGerado + int(binary.BigEndian.Uint64(soma[:8])%PrimeiroIDGerado)
+}
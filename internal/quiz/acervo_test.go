@@ -0,0 +1,78 @@
+package quiz
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func questaoAcervo() Questao {
+	return Questao{
+		Questao:     "Qual operador declara e inicializa uma variável?",
+		Opcoes:      []string{":=", "=", "==", "<-"},
+		Resposta:    ":=",
+		Explicacao:  "A declaração curta usa :=.",
+		Dificuldade: "facil",
+		Categoria:   "sintaxe",
+	}
+}
+
+func TestIDConteudo(t *testing.T) {
+	base := questaoAcervo()
+	id := IDConteudo(base)
+	if id < PrimeiroIDGerado || id >= 2*PrimeiroIDGerado {
+		t.Fatalf("ID %d fora da faixa das questões geradas", id)
+	}
+
+	variacoes := map[string]func(*Questao){
+		"espaços":            func(q *Questao) { q.Questao = "  Qual operador   declara e\ninicializa uma variável? " },
+		"caixa":              func(q *Questao) { q.Questao = "QUAL OPERADOR DECLARA E INICIALIZA UMA VARIÁVEL?" },
+		"acentos":            func(q *Questao) { q.Questao = "Qual operador declara e inicializa uma variavel?" },
+		"pontuação final":    func(q *Questao) { q.Questao = "Qual operador declara e inicializa uma variável" },
+		"ordem das opções":   func(q *Questao) { q.Opcoes = []string{"<-", "==", "=", ":="} },
+		"explicação e resto": func(q *Questao) { q.Explicacao, q.Dificuldade = "Outra.", "medio" },
+	}
+	for nome, variar := range variacoes {
+		q := questaoAcervo()
+		variar(&q)
+		if got := IDConteudo(q); got != id {
+			t.Errorf("%s: ID %d, esperado o mesmo %d", nome, got, id)
+		}
+	}
+
+	// Símbolos no meio do texto contam: ":=" e "=" são respostas diferentes.
+	diferente := questaoAcervo()
+	diferente.Opcoes = []string{"=", "==", "<-", "var"}
+	diferente.Resposta = "var"
+	if IDConteudo(diferente) == id {
+		t.Error("opções diferentes deveriam mudar o ID")
+	}
+}
+
+func TestAcervoIgnoraRepetidas(t *testing.T) {
+	arquivo := filepath.Join(t.TempDir(), "geradas.json")
+	a, err := AbrirAcervo(arquivo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repetida := questaoAcervo()
+	repetida.Questao = "qual operador declara e inicializa uma VARIAVEL"
+	if !a.Adicionar(questaoAcervo(), "modelo") || a.Adicionar(repetida, "modelo") {
+		t.Fatal("a primeira questão deveria entrar e a repetida, não")
+	}
+	if err := a.Salvar(); err != nil {
+		t.Fatal(err)
+	}
+
+	relido, err := AbrirAcervo(arquivo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	questoes := relido.Questoes()
+	if len(questoes) != 1 || questoes[0].ID != IDConteudo(questaoAcervo()) {
+		t.Fatalf("questões relidas = %+v", questoes)
+	}
+	if relido.Adicionar(repetida, "modelo") {
+		t.Error("a repetida não deveria entrar depois de reabrir o acervo")
+	}
+}
@@ -0,0 +1,102 @@
+package quiz
+
+import (
+	"context"
+	"math/rand"
+
+	"quiz_go/internal/stats"
+)
+
+// dificuldadeAdaptativa escolhe a dificuldade da próxima questão: o nível
+// mais próximo da habilidade estimada, um acima depois de dois acertos
+// seguidos e um abaixo depois de um erro.
+func dificuldadeAdaptativa(habilidade float64, sequencia int) string {
+	nivel := stats.NivelHabilidade(habilidade)
+	switch {
+	case sequencia >= 2:
+		return stats.AjustarNivel(nivel, 1)
+	case sequencia < 0:
+		return stats.AjustarNivel(nivel, -1)
+	}
+	return nivel
+}
+
+// iniciarAdaptativo entrega as questões uma de cada vez: a próxima só é
+// escolhida (ou gerada) depois que a Sessao informa, por retorno, a
+// resposta da anterior, para que a dificuldade acompanhe o desempenho.
+func (q *Quiz) iniciarAdaptativo(ctx context.Context, quantidade int, categoria string) (questoes <-chan Questao, total int, retorno chan<- stats.Resposta) {
+	canal := make(chan Questao)
+	respostas := make(chan stats.Resposta, quantidade)
+	habilidade := q.stats.HabilidadeAtual()
+
+	go func() {
+		defer close(canal)
+
+		sequencia := 0 // acertos seguidos (positivo) ou erros seguidos (negativo)
+		reserva := NovoGeradorEstatico(q.questoes)
+		var geradas []Questao
+		defer func() { _ = q.guardarGeradas(geradas...) }()
+
+		for i := 0; i < quantidade; i++ {
+			pedido := PedidoQuestao{Dificuldade: dificuldadeAdaptativa(habilidade, sequencia), Categoria: categoria}
+			if pedido.Categoria == "" {
+				pedido.Categoria = Categorias[rand.Intn(len(Categorias))]
+			}
+
+			questao, gerada := q.questaoAdaptativa(ctx, reserva, pedido)
+			if questao == nil {
+				return
+			}
+			if gerada {
+				geradas = append(geradas, *questao)
+			}
+
+			select {
+			case canal <- *questao:
+			case <-ctx.Done():
+				return
+			}
+
+			var r stats.Resposta
+			select {
+			case r = <-respostas:
+			case <-ctx.Done():
+				return
+			}
+			if r.Escolhida == "" && !r.TempoEsgotado {
+				continue // erro ao ler a resposta: mantém o nível
+			}
+
+			habilidade = stats.AtualizarHabilidade(habilidade, r.Dificuldade, r.Correta)
+			switch {
+			case r.Correta && sequencia >= 0:
+				sequencia++
+			case r.Correta:
+				sequencia = 1
+			case sequencia <= 0:
+				sequencia--
+			default:
+				sequencia = -1
+			}
+		}
+	}()
+
+	return canal, quantidade, respostas
+}
+
+// questaoAdaptativa gera a questão pedida com a IA, se ativa, ou a busca no
+// banco. Retorna também se a questão veio da IA.
+func (q *Quiz) questaoAdaptativa(ctx context.Context, reserva *GeradorEstatico, pedido PedidoQuestao) (*Questao, bool) {
+	if q.usarIA && ctx.Err() == nil {
+		res := gerarComLimite(ctx, q.gerador, 0, pedido, q.config.TimeoutGeracao)
+		if res.Err == nil {
+			return res.Questao, true
+		}
+	}
+
+	questao, err := reserva.Gerar(context.Background(), pedido)
+	if err != nil {
+		return nil, false
+	}
+	return questao, false
+}
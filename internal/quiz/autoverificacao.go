@@ -0,0 +1,278 @@
+package quiz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"quiz_go/internal/config"
+)
+
+// Modos aceitos na chave "autoverificacao" da configuração.
+const (
+	AutoverificacaoLigada    = "ligada"
+	AutoverificacaoDesligada = "desligada"
+)
+
+// Respondedor responde a uma questão sem ver a resposta. É usado para
+// conferir as questões geradas: um modelo que não chega à mesma resposta
+// indica uma questão errada ou ambígua.
+type Respondedor interface {
+	Nome() string
+	// Responder retorna as opções escolhidas ou o texto da resposta, no
+	// formato aceito por Questao.Corrigir.
+	Responder(ctx context.Context, questao Questao) ([]string, error)
+}
+
+// NovoRespondedor cria o respondedor da autoverificação com o mesmo servidor
+// e o mesmo cliente de cfg.Gerador. O modelo é cfg.ModeloAutoverificacao ou, se vazio, o
+// mesmo que gera as questões. A temperatura é zero para a resposta ser a
+// mais provável para o modelo.
+func NovoRespondedor(cfg config.Config, cliente *http.Client) (Respondedor, error) {
+	cfg.Temperatura = 0
+	if cfg.ModeloAutoverificacao != "" {
+		cfg.OllamaModelo = cfg.ModeloAutoverificacao
+		cfg.OpenAIModelo = cfg.ModeloAutoverificacao
+	}
+
+	gerador, err := NovoGerador(cfg, nil, cliente)
+	if err != nil {
+		return nil, err
+	}
+	respondedor, ok := gerador.(Respondedor)
+	if !ok {
+		return nil, fmt.Errorf("o gerador '%s' não responde questões", cfg.Gerador)
+	}
+	return respondedor, nil
+}
+
+// montarPromptResposta pede ao modelo a resposta de uma questão, sem dizer
+// qual é a correta.
+func montarPromptResposta(questao Questao) string {
+	var b strings.Builder
+	b.WriteString("Responda à questão de programação Go abaixo.\n\n")
+	b.WriteString("Questão: " + questao.Questao + "\n")
+	if questao.Codigo != "" {
+		b.WriteString("\nCódigo:\n```go\n" + strings.TrimRight(questao.Codigo, "\n") + "\n```\n")
+	}
+
+	opcoes := questao.OpcoesExibidas()
+	if len(opcoes) > 0 {
+		b.WriteString("\nOpções:\n")
+		for _, opcao := range opcoes {
+			b.WriteString("- " + opcao + "\n")
+		}
+	}
+
+	b.WriteString("\nRetorne APENAS um JSON válido no seguinte formato:\n")
+	switch {
+	case questao.TipoQuestao() == TipoMultiplasRespostas:
+		b.WriteString(`{"respostas": ["cada opção correta, exatamente como escrita acima"]}` + "\n\nPode haver mais de uma opção correta.")
+	case questao.TipoQuestao() == TipoVerdadeiroFalso:
+		b.WriteString(`{"resposta": "Verdadeiro" ou "Falso"}`)
+	case questao.TipoQuestao() == TipoSaidaCodigo && len(opcoes) == 0:
+		b.WriteString(`{"resposta": "saída exata do programa"}`)
+	case questao.RespostaDigitada():
+		b.WriteString(`{"resposta": "resposta curta, com poucas palavras"}`)
+	default:
+		b.WriteString(`{"resposta": "a opção correta, exatamente como escrita acima"}`)
+	}
+	return b.String()
+}
+
+// esquemaResposta é o JSON Schema da resposta pedida em montarPromptResposta.
+func esquemaResposta(questao Questao) map[string]any {
+	texto := map[string]any{"type": "string"}
+	if opcoes := questao.OpcoesExibidas(); len(opcoes) > 0 {
+		texto["enum"] = opcoes
+	}
+
+	if questao.TipoQuestao() == TipoMultiplasRespostas {
+		return map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"respostas": map[string]any{"type": "array", "items": texto, "minItems": 1}},
+			"required":   []string{"respostas"},
+		}
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"resposta": texto},
+		"required":   []string{"resposta"},
+	}
+}
+
+// interpretarResposta extrai a resposta do texto devolvido pelo modelo,
+// ignorando texto extra em volta do JSON.
+func interpretarResposta(questao Questao, resposta string) ([]string, error) {
+	inicio := strings.Index(resposta, "{")
+	fim := strings.LastIndex(resposta, "}")
+	if inicio == -1 || fim < inicio {
+		return nil, fmt.Errorf("JSON não encontrado na resposta")
+	}
+
+	var conteudo struct {
+		Resposta  string   `json:"resposta"`
+		Respostas []string `json:"respostas"`
+	}
+	if err := json.Unmarshal([]byte(resposta[inicio:fim+1]), &conteudo); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta: %v", err)
+	}
+
+	if questao.TipoQuestao() == TipoMultiplasRespostas {
+		return conteudo.Respostas, nil
+	}
+	return []string{conteudo.Resposta}, nil
+}
+
+// GeradorAutoverificado pede a Respondedor a resposta de cada questão
+// gerada, sem mostrar a correta, e descarta a questão com erro quando as
+// respostas não batem. Questões já conferidas executando o código
+// (Verificada) não passam pelo modelo.
+type GeradorAutoverificado struct {
+	GeradorQuestoes
+	Respondedor Respondedor
+	Registro    *RegistroAutoverificacao // opcional
+}
+
+func (g *GeradorAutoverificado) Gerar(ctx context.Context, pedido PedidoQuestao) (*Questao, error) {
+	questao, err := g.GeradorQuestoes.Gerar(ctx, pedido)
+	if err != nil || questao.Verificada {
+		return questao, err
+	}
+
+	resposta, err := g.Respondedor.Responder(ctx, *questao)
+	if err != nil {
+		return nil, fmt.Errorf("erro na autoverificação com %s: %v", g.Respondedor.Nome(), err)
+	}
+
+	concorda := questao.Corrigir(resposta)
+	if g.Registro != nil {
+		g.Registro.Registrar(g.GeradorQuestoes.Nome(), g.Respondedor.Nome(), concorda)
+	}
+	if !concorda {
+		return nil, fmt.Errorf("questão descartada na autoverificação: %s respondeu '%s', mas a resposta gerada é '%s'",
+			g.Respondedor.Nome(), strings.Join(resposta, "; "), questao.RespostaEsperada())
+	}
+	return questao, nil
+}
+
+// Concordancia conta as questões de um gerador conferidas por um
+// respondedor.
+type Concordancia struct {
+	Gerador     string `json:"gerador"`
+	Respondedor string `json:"respondedor"`
+	Concordou   int    `json:"concordou"`
+	Discordou   int    `json:"discordou"`
+}
+
+// Taxa retorna a fração das questões em que o respondedor chegou à mesma
+// resposta.
+func (c Concordancia) Taxa() float64 {
+	total := c.Concordou + c.Discordou
+	if total == 0 {
+		return 0
+	}
+	return float64(c.Concordou) / float64(total)
+}
+
+// RegistroAutoverificacao acumula em disco a concordância de cada par de
+// gerador e respondedor entre as sessões. É seguro para uso concorrente.
+type RegistroAutoverificacao struct {
+	arquivo string
+
+	mu       sync.Mutex
+	pares    []Concordancia
+	alterado bool
+}
+
+// AbrirRegistroAutoverificacao lê o registro de arquivo. Um arquivo
+// inexistente resulta num registro vazio, criado no primeiro Salvar.
+func AbrirRegistroAutoverificacao(arquivo string) (*RegistroAutoverificacao, error) {
+	r := &RegistroAutoverificacao{arquivo: arquivo}
+
+	data, err := os.ReadFile(arquivo)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler registro de autoverificação: %v", err)
+	}
+
+	var conteudo struct {
+		Modelos []Concordancia `json:"modelos"`
+	}
+	if err := json.Unmarshal(data, &conteudo); err != nil {
+		return nil, fmt.Errorf("%s: JSON inválido: %v", arquivo, err)
+	}
+	r.pares = conteudo.Modelos
+	return r, nil
+}
+
+// Registrar conta uma questão do gerador conferida pelo respondedor.
+func (r *RegistroAutoverificacao) Registrar(gerador, respondedor string, concorda bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := 0
+	for i < len(r.pares) && (r.pares[i].Gerador != gerador || r.pares[i].Respondedor != respondedor) {
+		i++
+	}
+	if i == len(r.pares) {
+		r.pares = append(r.pares, Concordancia{Gerador: gerador, Respondedor: respondedor})
+	}
+	if concorda {
+		r.pares[i].Concordou++
+	} else {
+		r.pares[i].Discordou++
+	}
+	r.alterado = true
+}
+
+// Concordancias retorna uma cópia das contagens, do par com mais questões
+// conferidas para o com menos.
+func (r *RegistroAutoverificacao) Concordancias() []Concordancia {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pares := append([]Concordancia(nil), r.pares...)
+	sort.SliceStable(pares, func(i, j int) bool {
+		return pares[i].Concordou+pares[i].Discordou > pares[j].Concordou+pares[j].Discordou
+	})
+	return pares
+}
+
+// Salvar grava o registro se houve questões conferidas desde a leitura.
+func (r *RegistroAutoverificacao) Salvar() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.alterado {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(struct {
+		Modelos []Concordancia `json:"modelos"`
+	}{r.pares}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar registro de autoverificação: %v", err)
+	}
+
+	if dir := filepath.Dir(r.arquivo); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("erro ao criar diretório %s: %v", dir, err)
+		}
+	}
+	if err := os.WriteFile(r.arquivo, data, 0644); err != nil {
+		return fmt.Errorf("erro ao salvar registro de autoverificação: %v", err)
+	}
+
+	r.alterado = false
+	return nil
+}
@@ -0,0 +1,365 @@
+package quiz
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErroRegistro descreve um registro inválido encontrado ao carregar o banco de questões.
+type ErroRegistro struct {
+	Arquivo string
+	Linha   int
+	Motivo  string
+}
+
+func (e *ErroRegistro) Error() string {
+	if e.Linha > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.Arquivo, e.Linha, e.Motivo)
+	}
+	return fmt.Sprintf("%s: %s", e.Arquivo, e.Motivo)
+}
+
+// origemQuestao guarda onde cada ID foi visto, para relatar duplicatas.
+type origemQuestao struct {
+	arquivo string
+	linha   int
+}
+
+type leitorBanco struct {
+	questoes []Questao
+	erros    []error
+	vistos   map[int]origemQuestao
+}
+
+// CarregarBanco lê questões de arquivos ou diretórios (JSON e YAML) e junta
+// tudo em um único banco. Registros inválidos ou com ID repetido são
+// ignorados e relatados no erro retornado, junto com o arquivo e a linha;
+// as questões válidas são retornadas mesmo quando há erros.
+func CarregarBanco(caminhos ...string) ([]Questao, error) {
+	l := novoLeitorBanco()
+	l.lerCaminhos(caminhos)
+	return l.questoes, errors.Join(l.erros...)
+}
+
+// CarregarBancoFS funciona como CarregarBanco, mas lê de um fs.FS
+// (por exemplo, arquivos embutidos no binário).
+func CarregarBancoFS(fsys fs.FS, caminhos ...string) ([]Questao, error) {
+	l := novoLeitorBanco()
+	l.lerCaminhosFS(fsys, caminhos)
+	return l.questoes, errors.Join(l.erros...)
+}
+
+// CarregarBancoCompleto carrega o pacote embutido seguido dos caminhos
+// informados, verificando IDs duplicados entre todos eles.
+func CarregarBancoCompleto(caminhos ...string) ([]Questao, error) {
+	l := novoLeitorBanco()
+	l.lerCaminhosFS(pacoteFS, []string{"pacote"})
+	l.lerCaminhos(caminhos)
+	return l.questoes, errors.Join(l.erros...)
+}
+
+// ValidarBanco aplica as regras de CarregarBanco a questões já lidas, vindas
+// de origem. Retorna as válidas e, no erro, as inválidas com a posição de
+// cada uma na lista, a partir de 1.
+func ValidarBanco(origem string, questoes []Questao) ([]Questao, error) {
+	l := novoLeitorBanco()
+	for i, questao := range questoes {
+		l.adicionar(origem, i+1, questao)
+	}
+	return l.questoes, errors.Join(l.erros...)
+}
+
+// SalvarBanco grava as questões em um arquivo JSON ou YAML, conforme a
+// extensão, no mesmo formato aceito por CarregarBanco.
+func SalvarBanco(arquivo string, questoes []Questao) error {
+	var data []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(arquivo)) {
+	case ".json":
+		data, err = json.MarshalIndent(questoes, "", "  ")
+	case ".yaml", ".yml":
+		data, err = yaml.Marshal(map[string][]Questao{"questoes": questoes})
+	default:
+		return fmt.Errorf("formato não suportado: %s (use .json, .yaml ou .yml)", arquivo)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao serializar questões: %v", err)
+	}
+
+	if dir := filepath.Dir(arquivo); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(arquivo, data, 0644)
+}
+
+func novoLeitorBanco() *leitorBanco {
+	return &leitorBanco{vistos: make(map[int]origemQuestao)}
+}
+
+func (l *leitorBanco) lerCaminhos(caminhos []string) {
+	for _, caminho := range caminhos {
+		info, err := os.Stat(caminho)
+		if err != nil {
+			l.erros = append(l.erros, fmt.Errorf("erro ao abrir banco de questões: %v", err))
+			continue
+		}
+
+		if !info.IsDir() {
+			l.lerArquivoOS(caminho)
+			continue
+		}
+
+		var arquivos []string
+		err = filepath.WalkDir(caminho, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && arquivoBanco(p) {
+				arquivos = append(arquivos, p)
+			}
+			return nil
+		})
+		if err != nil {
+			l.erros = append(l.erros, fmt.Errorf("erro ao percorrer %s: %v", caminho, err))
+		}
+
+		sort.Strings(arquivos)
+		for _, arquivo := range arquivos {
+			l.lerArquivoOS(arquivo)
+		}
+	}
+}
+
+func (l *leitorBanco) lerCaminhosFS(fsys fs.FS, caminhos []string) {
+	for _, caminho := range caminhos {
+		var arquivos []string
+		err := fs.WalkDir(fsys, caminho, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && arquivoBanco(p) {
+				arquivos = append(arquivos, p)
+			}
+			return nil
+		})
+		if err != nil {
+			l.erros = append(l.erros, fmt.Errorf("erro ao percorrer %s: %v", caminho, err))
+			continue
+		}
+
+		sort.Strings(arquivos)
+		for _, arquivo := range arquivos {
+			data, err := fs.ReadFile(fsys, arquivo)
+			if err != nil {
+				l.erros = append(l.erros, &ErroRegistro{Arquivo: arquivo, Motivo: err.Error()})
+				continue
+			}
+			l.lerDados(arquivo, data)
+		}
+	}
+}
+
+func arquivoBanco(nome string) bool {
+	switch strings.ToLower(path.Ext(filepath.ToSlash(nome))) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+func (l *leitorBanco) lerArquivoOS(arquivo string) {
+	data, err := os.ReadFile(arquivo)
+	if err != nil {
+		l.erros = append(l.erros, &ErroRegistro{Arquivo: arquivo, Motivo: err.Error()})
+		return
+	}
+	l.lerDados(arquivo, data)
+}
+
+func (l *leitorBanco) lerDados(arquivo string, data []byte) {
+	var err error
+	switch strings.ToLower(path.Ext(filepath.ToSlash(arquivo))) {
+	case ".json":
+		err = l.lerJSON(arquivo, data)
+	case ".yaml", ".yml":
+		err = l.lerYAML(arquivo, data)
+	default:
+		err = fmt.Errorf("formato não suportado")
+	}
+	if err != nil {
+		l.erros = append(l.erros, &ErroRegistro{Arquivo: arquivo, Motivo: err.Error()})
+	}
+}
+
+// lerJSON aceita uma lista de questões ou um objeto com a chave "questoes".
+func (l *leitorBanco) lerJSON(arquivo string, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("JSON inválido: %v", err)
+	}
+
+	switch tok {
+	case json.Delim('['):
+		return l.lerListaJSON(arquivo, data, dec)
+	case json.Delim('{'):
+		for dec.More() {
+			chave, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("JSON inválido: %v", err)
+			}
+			if chave != "questoes" {
+				var ignorado json.RawMessage
+				if err := dec.Decode(&ignorado); err != nil {
+					return fmt.Errorf("JSON inválido: %v", err)
+				}
+				continue
+			}
+			tok, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("JSON inválido: %v", err)
+			}
+			if tok != json.Delim('[') {
+				return fmt.Errorf("a chave \"questoes\" deve conter uma lista")
+			}
+			return l.lerListaJSON(arquivo, data, dec)
+		}
+		return fmt.Errorf("chave \"questoes\" não encontrada")
+	default:
+		return fmt.Errorf("esperado uma lista de questões ou um objeto com \"questoes\"")
+	}
+}
+
+func (l *leitorBanco) lerListaJSON(arquivo string, data []byte, dec *json.Decoder) error {
+	for dec.More() {
+		linha := linhaDoOffset(data, dec.InputOffset())
+
+		var questao Questao
+		if err := dec.Decode(&questao); err != nil {
+			var tipoErr *json.UnmarshalTypeError
+			if !errors.As(err, &tipoErr) {
+				return fmt.Errorf("linha %d: JSON inválido: %v", linha, err)
+			}
+			l.erros = append(l.erros, &ErroRegistro{Arquivo: arquivo, Linha: linha, Motivo: err.Error()})
+			continue
+		}
+		l.adicionar(arquivo, linha, questao)
+	}
+	if _, err := dec.Token(); err != nil && err != io.EOF {
+		return fmt.Errorf("JSON inválido: %v", err)
+	}
+	return nil
+}
+
+// linhaDoOffset converte o offset do decoder na linha onde o próximo
+// registro começa, pulando espaços e vírgulas entre os elementos.
+func linhaDoOffset(data []byte, offset int64) int {
+	i := int(offset)
+	for i < len(data) && strings.ContainsRune(" \t\r\n,", rune(data[i])) {
+		i++
+	}
+	return bytes.Count(data[:i], []byte("\n")) + 1
+}
+
+// lerYAML aceita uma lista de questões ou um mapa com a chave "questoes".
+func (l *leitorBanco) lerYAML(arquivo string, data []byte) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("YAML inválido: %v", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	raiz := doc.Content[0]
+	if raiz.Kind == yaml.MappingNode {
+		var lista *yaml.Node
+		for i := 0; i+1 < len(raiz.Content); i += 2 {
+			if raiz.Content[i].Value == "questoes" {
+				lista = raiz.Content[i+1]
+				break
+			}
+		}
+		if lista == nil {
+			return fmt.Errorf("chave \"questoes\" não encontrada")
+		}
+		raiz = lista
+	}
+	if raiz.Kind != yaml.SequenceNode {
+		return fmt.Errorf("linha %d: esperado uma lista de questões", raiz.Line)
+	}
+
+	for _, no := range raiz.Content {
+		var questao Questao
+		if err := no.Decode(&questao); err != nil {
+			l.erros = append(l.erros, &ErroRegistro{Arquivo: arquivo, Linha: no.Line, Motivo: err.Error()})
+			continue
+		}
+		l.adicionar(arquivo, no.Line, questao)
+	}
+	return nil
+}
+
+func (l *leitorBanco) adicionar(arquivo string, linha int, questao Questao) {
+	if err := validarRegistro(&questao); err != nil {
+		l.erros = append(l.erros, &ErroRegistro{Arquivo: arquivo, Linha: linha, Motivo: err.Error()})
+		return
+	}
+
+	if origem, ok := l.vistos[questao.ID]; ok {
+		l.erros = append(l.erros, &ErroRegistro{
+			Arquivo: arquivo,
+			Linha:   linha,
+			Motivo:  fmt.Sprintf("ID %d duplicado (já definido em %s:%d)", questao.ID, origem.arquivo, origem.linha),
+		})
+		return
+	}
+
+	l.vistos[questao.ID] = origemQuestao{arquivo: arquivo, linha: linha}
+	l.questoes = append(l.questoes, questao)
+}
+
+// validarRegistro aplica as regras de validarQuestao e exige os campos
+// que só fazem sentido em questões vindas de um banco.
+func validarRegistro(questao *Questao) error {
+	if questao.ID <= 0 {
+		return fmt.Errorf("ID deve ser um número positivo, encontrado: %d", questao.ID)
+	}
+
+	if err := validarQuestao(&QuestaoGerada{
+		Tipo:      questao.Tipo,
+		Questao:   questao.Questao,
+		Codigo:    questao.Codigo,
+		Opcoes:    questao.Opcoes,
+		Resposta:  questao.Resposta,
+		Respostas: questao.Respostas,
+	}); err != nil {
+		return err
+	}
+
+	switch questao.Dificuldade {
+	case "facil", "medio", "dificil":
+	default:
+		return fmt.Errorf("dificuldade '%s' inválida (use facil, medio ou dificil)", questao.Dificuldade)
+	}
+
+	if strings.TrimSpace(questao.Categoria) == "" {
+		return fmt.Errorf("categoria vazia")
+	}
+
+	return nil
+}
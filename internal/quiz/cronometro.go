@@ -0,0 +1,65 @@
+package quiz
+
+import "time"
+
+const (
+	// tempoReferenciaBonus é o tempo usado no bônus de velocidade quando
+	// não há limite por questão.
+	tempoReferenciaBonus = 30 * time.Second
+
+	// toleranciaPrazo desconta o atraso entre o fim do prazo e a chegada da
+	// resposta, como nos terminais onde a pergunta não pode ser interrompida.
+	toleranciaPrazo = 500 * time.Millisecond
+)
+
+// cronometro guarda os limites de tempo de um quiz: o prazo de cada questão
+// (tempo_questao) e o fim da prova, no modo Prova. Limites zerados não se
+// aplicam.
+type cronometro struct {
+	porQuestao time.Duration
+	fimProva   time.Time
+}
+
+func novoCronometro(porQuestao, duracaoProva time.Duration) *cronometro {
+	c := &cronometro{porQuestao: porQuestao}
+	if duracaoProva > 0 {
+		c.fimProva = time.Now().Add(duracaoProva)
+	}
+	return c
+}
+
+// pausar estende o fim da prova por um tempo em que o jogador não pôde
+// responder, como a espera pela geração da próxima questão.
+func (c *cronometro) pausar(duracao time.Duration) {
+	if !c.fimProva.IsZero() {
+		c.fimProva = c.fimProva.Add(duracao)
+	}
+}
+
+// prazoQuestao retorna o prazo de uma questão mostrada em inicio: o menor
+// entre o limite da questão e o fim da prova, ou zero se não houver limite.
+func (c *cronometro) prazoQuestao(inicio time.Time) time.Time {
+	prazo := c.fimProva
+	if c.porQuestao > 0 {
+		fim := inicio.Add(c.porQuestao)
+		if prazo.IsZero() || fim.Before(prazo) {
+			prazo = fim
+		}
+	}
+	return prazo
+}
+
+func (c *cronometro) provaEncerrada() bool {
+	return !c.fimProva.IsZero() && !time.Now().Before(c.fimProva)
+}
+
+// bonus calcula os pontos extras de um acerto: o máximo configurado para
+// uma resposta imediata, diminuindo até zero no limite da questão.
+func (c *cronometro) bonus(maximo float64, duracao time.Duration) float64 {
+	referencia := c.porQuestao
+	if referencia <= 0 {
+		referencia = tempoReferenciaBonus
+	}
+	fracao := 1 - float64(duracao)/float64(referencia)
+	return maximo * max(0, fracao)
+}
@@ -0,0 +1,159 @@
+package quiz
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// OpcoesGeracao controla a geração concorrente de questões.
+type OpcoesGeracao struct {
+	Trabalhadores int           // requisições simultâneas ao gerador
+	Intervalo     time.Duration // intervalo mínimo entre o início de duas requisições
+	Timeout       time.Duration // tempo limite de cada requisição
+}
+
+// ResultadoGeracao é o resultado do pedido de posição Indice. Questao é nil
+// quando Err não é nil.
+type ResultadoGeracao struct {
+	Indice  int
+	Pedido  PedidoQuestao
+	Questao *Questao
+	Err     error
+}
+
+// montarPedidos sorteia a dificuldade e a categoria de cada questão quando
+// elas não foram fixadas.
+func montarPedidos(quantidade int, dificuldade, categoria, tipo string) []PedidoQuestao {
+	pedidos := make([]PedidoQuestao, quantidade)
+	for i := range pedidos {
+		pedidos[i] = PedidoQuestao{Dificuldade: dificuldade, Categoria: categoria, Tipo: tipo}
+		if pedidos[i].Dificuldade == "" {
+			pedidos[i].Dificuldade = Dificuldades[rand.Intn(len(Dificuldades))]
+		}
+		if pedidos[i].Categoria == "" {
+			pedidos[i].Categoria = Categorias[rand.Intn(len(Categorias))]
+		}
+	}
+	return pedidos
+}
+
+// GerarEmParalelo atende os pedidos com um pool de opcoes.Trabalhadores
+// goroutines e entrega os resultados no canal retornado na mesma ordem dos
+// pedidos, assim que cada um fica pronto. O canal recebe exatamente um
+// resultado por pedido e é fechado no fim. Cancelar ctx interrompe as
+// requisições em andamento; os pedidos restantes chegam com ctx.Err().
+func GerarEmParalelo(ctx context.Context, gerador GeradorQuestoes, pedidos []PedidoQuestao, opcoes OpcoesGeracao) <-chan ResultadoGeracao {
+	trabalhadores := opcoes.Trabalhadores
+	if trabalhadores < 1 {
+		trabalhadores = 1
+	}
+	if trabalhadores > len(pedidos) {
+		trabalhadores = len(pedidos)
+	}
+
+	// Os canais têm espaço para todos os resultados, então nenhuma goroutine
+	// fica presa se quem consome parar de ler.
+	fila := make(chan int)
+	prontos := make(chan ResultadoGeracao, len(pedidos))
+	saida := make(chan ResultadoGeracao, len(pedidos))
+
+	limitador, pararLimitador := novoLimitador(opcoes.Intervalo)
+
+	var wg sync.WaitGroup
+	for w := 0; w < trabalhadores; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range fila {
+				select {
+				case <-limitador:
+				case <-ctx.Done():
+				}
+				prontos <- gerarComLimite(ctx, gerador, i, pedidos[i], opcoes.Timeout)
+			}
+		}()
+	}
+
+	go func() {
+		for i := range pedidos {
+			fila <- i
+		}
+		close(fila)
+		wg.Wait()
+		pararLimitador()
+		close(prontos)
+	}()
+
+	// Reordena os resultados: guarda os que chegam adiantados até que os
+	// anteriores fiquem prontos.
+	go func() {
+		defer close(saida)
+		pendentes := make(map[int]ResultadoGeracao)
+		proximo := 0
+		for res := range prontos {
+			pendentes[res.Indice] = res
+			for {
+				r, ok := pendentes[proximo]
+				if !ok {
+					break
+				}
+				delete(pendentes, proximo)
+				saida <- r
+				proximo++
+			}
+		}
+	}()
+
+	return saida
+}
+
+// novoLimitador devolve um canal que libera uma requisição por intervalo. A
+// primeira é liberada imediatamente; intervalo zero não limita nada.
+func novoLimitador(intervalo time.Duration) (<-chan struct{}, func()) {
+	limitador := make(chan struct{}, 1)
+	limitador <- struct{}{}
+	if intervalo <= 0 {
+		close(limitador)
+		return limitador, func() {}
+	}
+
+	ticker := time.NewTicker(intervalo)
+	fim := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				select {
+				case limitador <- struct{}{}:
+				default:
+				}
+			case <-fim:
+				return
+			}
+		}
+	}()
+
+	return limitador, func() {
+		ticker.Stop()
+		close(fim)
+	}
+}
+
+func gerarComLimite(ctx context.Context, gerador GeradorQuestoes, indice int, pedido PedidoQuestao, timeout time.Duration) ResultadoGeracao {
+	res := ResultadoGeracao{Indice: indice, Pedido: pedido}
+	if err := ctx.Err(); err != nil {
+		res.Err = err
+		return res
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	res.Questao, res.Err = gerador.Gerar(ctx, pedido)
+	return res
+}
@@ -0,0 +1,110 @@
+package quiz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"quiz_go/internal/ollamafalso"
+)
+
+// questaoNumerada é a questaoValida com o enunciado "Questão n".
+func questaoNumerada(n int) ollamafalso.Resposta {
+	questao := questaoValida()
+	questao["questao"] = fmt.Sprintf("Questão %d", n)
+	return ollamafalso.JSON(questao)
+}
+
+func receberTodos(t *testing.T, canal <-chan ResultadoGeracao) []ResultadoGeracao {
+	t.Helper()
+	var resultados []ResultadoGeracao
+	limite := time.After(5 * time.Second)
+	for {
+		select {
+		case res, ok := <-canal:
+			if !ok {
+				return resultados
+			}
+			resultados = append(resultados, res)
+		case <-limite:
+			t.Fatalf("o canal não foi fechado; %d resultados recebidos", len(resultados))
+		}
+	}
+}
+
+func TestGerarEmParaleloEntregaNaOrdemDosPedidos(t *testing.T) {
+	// As requisições simultâneas chegam em qualquer ordem, então cada
+	// pedido tem uma categoria e a sua resposta. Elas ficam prontas fora de
+	// ordem, e a segunda passa do tempo limite.
+	categorias := Categorias[:4]
+	atrasos := []time.Duration{200 * time.Millisecond, 10 * time.Second, 0, 50 * time.Millisecond}
+	s := ollamafalso.Novo(t)
+	pedidos := make([]PedidoQuestao, len(categorias))
+	for i, categoria := range categorias {
+		pedidos[i] = PedidoQuestao{Dificuldade: "facil", Categoria: categoria}
+		s.Adicionar(ollamafalso.Para("Categoria: "+categoria+"\n", ollamafalso.Lento(atrasos[i], questaoNumerada(i))))
+	}
+	opcoes := OpcoesGeracao{Trabalhadores: 4, Intervalo: 40 * time.Millisecond, Timeout: 400 * time.Millisecond}
+
+	inicio := time.Now()
+	resultados := receberTodos(t, GerarEmParalelo(context.Background(), novoGeradorOllama(s), pedidos, opcoes))
+
+	if len(resultados) != len(pedidos) {
+		t.Fatalf("%d resultados, esperado %d", len(resultados), len(pedidos))
+	}
+	for i, res := range resultados {
+		if res.Indice != i || res.Pedido.Categoria != categorias[i] {
+			t.Errorf("resultado %d é do pedido %d (%s)", i, res.Indice, res.Pedido.Categoria)
+		}
+		if i == 1 {
+			if res.Err == nil || res.Questao != nil {
+				t.Errorf("o pedido lento deveria falhar pelo tempo limite, veio %+v", res)
+			}
+			continue
+		}
+		if res.Err != nil || res.Questao.Questao != fmt.Sprintf("Questão %d", i) {
+			t.Errorf("resultado %d = %+v", i, res)
+		}
+	}
+	if d := time.Since(inicio); d > 2*time.Second {
+		t.Errorf("a geração levou %v: o pedido lento não deveria segurar os outros", d)
+	}
+
+	// O limitador espaça o início das requisições, com uma folga para o
+	// relógio do ticker.
+	reqs := s.Requisicoes()
+	for i := 1; i < len(reqs); i++ {
+		if d := reqs[i].Chegada.Sub(reqs[i-1].Chegada); d < opcoes.Intervalo*3/4 {
+			t.Errorf("requisições %d e %d com %v de intervalo, esperado %v", i-1, i, d, opcoes.Intervalo)
+		}
+	}
+}
+
+func TestGerarEmParaleloCancelado(t *testing.T) {
+	s := ollamafalso.Novo(t, ollamafalso.Lento(10*time.Second, questaoNumerada(0)))
+	ctx, cancel := context.WithCancel(context.Background())
+	pedidos := montarPedidos(3, "facil", "sintaxe", "")
+
+	canal := GerarEmParalelo(ctx, novoGeradorOllama(s), pedidos, OpcoesGeracao{Trabalhadores: 1})
+	time.AfterFunc(50*time.Millisecond, cancel)
+	resultados := receberTodos(t, canal)
+
+	if len(resultados) != len(pedidos) {
+		t.Fatalf("%d resultados, esperado um por pedido", len(resultados))
+	}
+	for i, res := range resultados {
+		if res.Err == nil || res.Questao != nil {
+			t.Errorf("resultado %d = %+v, esperado erro", i, res)
+		}
+	}
+	for _, res := range resultados[1:] {
+		if !errors.Is(res.Err, context.Canceled) {
+			t.Errorf("pedido %d: erro %v, esperado context.Canceled", res.Indice, res.Err)
+		}
+	}
+	if n := len(s.Requisicoes()); n != 1 {
+		t.Errorf("%d requisições, esperado só a que estava em andamento", n)
+	}
+}
@@ -0,0 +1,408 @@
+package quiz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+
+	"quiz_go/internal/config"
+)
+
+// Nomes aceitos na chave "gerador" da configuração.
+const (
+	GeradorNomeOllama   = "ollama"
+	GeradorNomeOpenAI   = "openai"
+	GeradorNomeEstatico = "estatico"
+)
+
+// PedidoQuestao descreve a questão que um gerador deve produzir.
+type PedidoQuestao struct {
+	Dificuldade string
+	Categoria   string
+	Tipo        string // vazio pede múltipla escolha
+
+	// Conceitos são explicações de questões que o jogador errou, para a
+	// questão gerada voltar a esses assuntos.
+	Conceitos []string
+}
+
+// GeradorQuestoes produz questões novas para uma dificuldade e uma categoria.
+type GeradorQuestoes interface {
+	// Nome identifica o gerador e o modelo em mensagens para o jogador.
+	Nome() string
+	// Disponivel retorna nil se o gerador pode ser usado agora.
+	Disponivel(ctx context.Context) error
+	Gerar(ctx context.Context, pedido PedidoQuestao) (*Questao, error)
+}
+
+// NovoGerador cria o gerador escolhido em cfg.Gerador. O banco é usado pelo
+// gerador estático e o cliente, pelos que acessam a rede (nil usa
+// http.DefaultClient).
+func NovoGerador(cfg config.Config, banco []Questao, cliente *http.Client) (GeradorQuestoes, error) {
+	if cliente == nil {
+		cliente = http.DefaultClient
+	}
+	switch cfg.Gerador {
+	case GeradorNomeOllama:
+		return &GeradorOllama{
+			URL:         cfg.OllamaURL,
+			Modelo:      cfg.OllamaModelo,
+			Temperatura: cfg.Temperatura,
+			Semente:     cfg.OllamaSemente,
+			Tentativas:  cfg.TentativasGeracao,
+			Cliente:     cliente,
+		}, nil
+	case GeradorNomeOpenAI:
+		return &GeradorOpenAI{
+			URL:         cfg.OpenAIURL,
+			Modelo:      cfg.OpenAIModelo,
+			ChaveAPI:    cfg.OpenAIChave,
+			Temperatura: cfg.Temperatura,
+			Tentativas:  cfg.TentativasGeracao,
+			Cliente:     cliente,
+		}, nil
+	case GeradorNomeEstatico:
+		return NovoGeradorEstatico(banco), nil
+	default:
+		return nil, fmt.Errorf("gerador '%s' desconhecido (use %s, %s ou %s)",
+			cfg.Gerador, GeradorNomeOllama, GeradorNomeOpenAI, GeradorNomeEstatico)
+	}
+}
+
+// VersaoPrompt identifica o prompt de montarPrompt nas questões guardadas.
+// Aumente ao mudar o prompt ou o formato pedido ao modelo.
+const VersaoPrompt = 5
+
+// formatoTipo descreve como pedir ao modelo uma questão de cada tipo.
+type formatoTipo struct {
+	descricao  string // completa "Gere uma questão ..."
+	campos     string // campos do JSON de exemplo próprios do tipo
+	requisitos []string
+}
+
+var formatosTipo = map[string]formatoTipo{
+	TipoMultiplaEscolha: {
+		descricao: "de múltipla escolha",
+		campos: `  "opcoes": ["opção 1", "opção 2", "opção 3", "opção 4"],
+  "resposta": "resposta correta exata (deve ser uma das opções)",`,
+		requisitos: []string{
+			"Deve ter exatamente 4 opções",
+			"Uma resposta deve estar correta",
+		},
+	},
+	TipoMultiplasRespostas: {
+		descricao: "de múltipla escolha com mais de uma resposta correta",
+		campos: `  "opcoes": ["opção 1", "opção 2", "opção 3", "opção 4", "opção 5"],
+  "respostas": ["cada opção correta, exatamente como em opcoes"],`,
+		requisitos: []string{
+			"Deve ter de 4 a 6 opções",
+			"Pelo menos uma opção deve estar correta e pelo menos uma errada",
+			"O enunciado deve deixar claro que há mais de uma resposta correta",
+		},
+	},
+	TipoVerdadeiroFalso: {
+		descricao: "de verdadeiro ou falso",
+		campos:    `  "resposta": "Verdadeiro" ou "Falso",`,
+		requisitos: []string{
+			"A questão deve ser uma afirmação claramente verdadeira ou claramente falsa",
+			"Não inclua opções",
+		},
+	},
+	TipoTextoLivre: {
+		descricao: "de resposta curta, digitada pelo jogador",
+		campos: `  "resposta": "resposta curta, como um nome de função, palavra-chave ou valor",
+  "alternativas": ["outras formas aceitas da mesma resposta"],`,
+		requisitos: []string{
+			"A resposta deve ter no máximo algumas palavras",
+			"Liste em alternativas grafias equivalentes (por exemplo, com e sem o nome do pacote)",
+			"Não inclua opções",
+		},
+	},
+	TipoSaidaCodigo: {
+		descricao: `do tipo "o que este código imprime?"`,
+		campos: `  "codigo": "programa Go completo, com package main e func main",
+  "resposta": "saída exata do programa",
+  "alternativas": [],`,
+		requisitos: []string{
+			"O código deve compilar e ter saída determinística (sem iterar mapas, sem horário, sem goroutines sem sincronização)",
+			"A resposta deve ser exatamente o que o programa imprime",
+			"Não inclua opções",
+		},
+	},
+}
+
+// tipoPedido retorna o tipo pedido, considerando o padrão.
+func tipoPedido(pedido PedidoQuestao) string {
+	if pedido.Tipo == "" {
+		return TipoMultiplaEscolha
+	}
+	return pedido.Tipo
+}
+
+// montarPrompt monta o pedido enviado aos modelos de linguagem.
+func montarPrompt(pedido PedidoQuestao) string {
+	tipo := tipoPedido(pedido)
+	formato := formatosTipo[tipo]
+	campos := formato.campos
+	if tipo != TipoSaidaCodigo {
+		campos = `  "codigo": "trecho de código Go citado na pergunta, ou vazio",
+` + campos
+	}
+	var requisitos strings.Builder
+	for _, requisito := range formato.requisitos {
+		requisitos.WriteString("- " + requisito + "\n")
+	}
+
+	prompt := fmt.Sprintf(`Gere uma questão %s sobre programação Go com as seguintes especificações:
+
+Dificuldade: %s
+Categoria: %s
+
+Retorne APENAS um JSON válido no seguinte formato:
+{
+  "tipo": "%s",
+  "questao": "Texto da pergunta aqui",
+%s
+  "explicacao": "Explicação detalhada da resposta",
+  "dificuldade": "%s",
+  "categoria": "%s"
+}
+
+Requisitos:
+- A questão deve ser sobre Go/Golang
+%s- Código vai no campo "codigo", nunca no texto da pergunta; na explicação, use blocos `+"```go"+`
+- A explicação deve ser educativa e de simples entendimento
+- Use português brasileiro
+- Não inclua texto adicional, apenas o JSON`, formato.descricao, pedido.Dificuldade, pedido.Categoria,
+		tipo, campos, pedido.Dificuldade, pedido.Categoria, requisitos.String())
+
+	if len(pedido.Conceitos) > 0 {
+		prompt += "\n\nO jogador errou recentemente questões com as explicações abaixo. Faça uma questão nova que exercite algum desses conceitos, sem repetir as perguntas:"
+		for _, conceito := range pedido.Conceitos {
+			prompt += "\n- " + conceito
+		}
+	}
+	return prompt
+}
+
+// decodificarQuestao decodifica e valida uma questão em JSON puro.
+func decodificarQuestao(jsonStr string) (*Questao, error) {
+	var questaoGerada QuestaoGerada
+	if err := json.Unmarshal([]byte(jsonStr), &questaoGerada); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar questão gerada: %v", err)
+	}
+
+	// Validar a questão gerada
+	if err := validarQuestao(&questaoGerada); err != nil {
+		return nil, fmt.Errorf("questão inválida: %v", err)
+	}
+
+	questao := &Questao{
+		Tipo:         questaoGerada.Tipo,
+		Questao:      questaoGerada.Questao,
+		Codigo:       questaoGerada.Codigo,
+		Opcoes:       questaoGerada.Opcoes,
+		Resposta:     questaoGerada.Resposta,
+		Respostas:    questaoGerada.Respostas,
+		Alternativas: questaoGerada.Alternativas,
+		Explicacao:   questaoGerada.Explicacao,
+		Dificuldade:  questaoGerada.Dificuldade,
+		Categoria:    questaoGerada.Categoria,
+	}
+	questao.ID = IDConteudo(*questao)
+
+	return questao, nil
+}
+
+// esquemaQuestao retorna o JSON Schema de QuestaoGerada para o tipo pedido,
+// enviado aos modelos que aceitam saída estruturada.
+func esquemaQuestao(tipo string) map[string]any {
+	texto := map[string]any{"type": "string"}
+	lista := func(minimo, maximo int) map[string]any {
+		esquema := map[string]any{"type": "array", "items": texto, "minItems": minimo}
+		if maximo > 0 {
+			esquema["maxItems"] = maximo
+		}
+		return esquema
+	}
+
+	propriedades := map[string]any{
+		"tipo":        map[string]any{"type": "string", "enum": []string{tipo}},
+		"questao":     texto,
+		"explicacao":  texto,
+		"dificuldade": map[string]any{"type": "string", "enum": Dificuldades},
+		"categoria":   texto,
+		"codigo":      texto,
+	}
+	obrigatorios := []string{"tipo", "questao", "explicacao", "dificuldade", "categoria"}
+
+	switch tipo {
+	case TipoMultiplaEscolha:
+		propriedades["opcoes"] = lista(4, 4)
+		propriedades["resposta"] = texto
+		obrigatorios = append(obrigatorios, "opcoes", "resposta")
+	case TipoMultiplasRespostas:
+		propriedades["opcoes"] = lista(4, 6)
+		propriedades["respostas"] = lista(1, 5)
+		obrigatorios = append(obrigatorios, "opcoes", "respostas")
+	case TipoVerdadeiroFalso:
+		propriedades["resposta"] = map[string]any{"type": "string", "enum": []string{Verdadeiro, Falso}}
+		obrigatorios = append(obrigatorios, "resposta")
+	case TipoTextoLivre:
+		propriedades["resposta"] = texto
+		propriedades["alternativas"] = lista(0, 0)
+		obrigatorios = append(obrigatorios, "resposta")
+	case TipoSaidaCodigo:
+		propriedades["resposta"] = texto
+		propriedades["alternativas"] = lista(0, 0)
+		obrigatorios = append(obrigatorios, "codigo", "resposta")
+	}
+
+	return map[string]any{
+		"type":       "object",
+		"properties": propriedades,
+		"required":   obrigatorios,
+	}
+}
+
+// TentativaFalha registra por que uma tentativa de geração foi descartada.
+type TentativaFalha struct {
+	Tentativa int
+	Resposta  string // texto devolvido pelo modelo
+	Motivo    string
+}
+
+// ErroGeracao é retornado quando nenhuma tentativa de gerar uma questão deu
+// certo. Err é o erro de comunicação que interrompeu as tentativas, se houve.
+type ErroGeracao struct {
+	Falhas []TentativaFalha
+	Err    error
+}
+
+func (e *ErroGeracao) Error() string {
+	motivos := make([]string, len(e.Falhas))
+	for i, f := range e.Falhas {
+		motivos[i] = fmt.Sprintf("tentativa %d: %s", f.Tentativa, f.Motivo)
+	}
+	msg := fmt.Sprintf("nenhuma resposta válida em %d tentativas (%s)", len(e.Falhas), strings.Join(motivos, "; "))
+	if e.Err != nil {
+		msg = fmt.Sprintf("%v; %s", e.Err, msg)
+	}
+	return msg
+}
+
+func (e *ErroGeracao) Unwrap() error {
+	return e.Err
+}
+
+// promptReparo pede ao modelo que corrija uma resposta rejeitada.
+func promptReparo(pedido PedidoQuestao, falha TentativaFalha) string {
+	return fmt.Sprintf(`%s
+
+Sua resposta anterior foi rejeitada.
+Resposta anterior:
+%s
+
+Motivo: %s
+
+Corrija o problema e retorne novamente apenas o JSON.`, montarPrompt(pedido), falha.Resposta, falha.Motivo)
+}
+
+// gerarComReparo chama enviar até obter uma questão válida, no máximo
+// tentativas vezes. A partir da segunda tentativa o prompt inclui a resposta
+// rejeitada e o motivo da rejeição. Erros de comunicação não são repetidos.
+func gerarComReparo(ctx context.Context, pedido PedidoQuestao, tentativas int,
+	enviar func(ctx context.Context, prompt string) (string, error),
+	interpretar func(resposta string) (*Questao, error)) (*Questao, error) {
+	if tentativas < 1 {
+		tentativas = 1
+	}
+
+	prompt := montarPrompt(pedido)
+	var falhas []TentativaFalha
+	for i := 1; i <= tentativas; i++ {
+		resposta, err := enviar(ctx, prompt)
+		if err != nil {
+			if len(falhas) > 0 {
+				return nil, &ErroGeracao{Falhas: falhas, Err: err}
+			}
+			return nil, err
+		}
+
+		questao, err := interpretar(resposta)
+		if err == nil && questao.TipoQuestao() != tipoPedido(pedido) {
+			err = fmt.Errorf("tipo '%s' diferente do pedido ('%s')", questao.TipoQuestao(), tipoPedido(pedido))
+		}
+		if err == nil {
+			return questao, nil
+		}
+
+		falha := TentativaFalha{Tentativa: i, Resposta: resposta, Motivo: err.Error()}
+		falhas = append(falhas, falha)
+		prompt = promptReparo(pedido, falha)
+	}
+
+	return nil, &ErroGeracao{Falhas: falhas}
+}
+
+// GeradorEstatico "gera" questões sorteando do banco, sem repetir enquanto
+// houver questões não usadas.
+type GeradorEstatico struct {
+	questoes []Questao
+	usadas   map[int]bool
+}
+
+func NovoGeradorEstatico(questoes []Questao) *GeradorEstatico {
+	return &GeradorEstatico{questoes: questoes, usadas: make(map[int]bool)}
+}
+
+func (g *GeradorEstatico) Nome() string {
+	return "Banco de questões"
+}
+
+func (g *GeradorEstatico) Disponivel(ctx context.Context) error {
+	if len(g.questoes) == 0 {
+		return fmt.Errorf("banco de questões vazio")
+	}
+	return nil
+}
+
+// Gerar busca uma questão com a dificuldade e a categoria pedidas, relaxando
+// primeiro a categoria e depois a dificuldade quando não há candidatas.
+func (g *GeradorEstatico) Gerar(ctx context.Context, pedido PedidoQuestao) (*Questao, error) {
+	filtros := []PedidoQuestao{
+		pedido,
+		{Dificuldade: pedido.Dificuldade, Tipo: pedido.Tipo},
+		{Dificuldade: pedido.Dificuldade},
+		{},
+	}
+
+	for _, filtro := range filtros {
+		var candidatas []Questao
+		for _, questao := range g.questoes {
+			if g.usadas[questao.ID] {
+				continue
+			}
+			if filtro.Dificuldade != "" && questao.Dificuldade != filtro.Dificuldade {
+				continue
+			}
+			if filtro.Categoria != "" && !strings.EqualFold(questao.Categoria, filtro.Categoria) {
+				continue
+			}
+			if filtro.Tipo != "" && questao.TipoQuestao() != filtro.Tipo {
+				continue
+			}
+			candidatas = append(candidatas, questao)
+		}
+		if len(candidatas) > 0 {
+			escolhida := candidatas[rand.Intn(len(candidatas))]
+			g.usadas[escolhida.ID] = true
+			return &escolhida, nil
+		}
+	}
+
+	return nil, fmt.Errorf("nenhuma questão disponível no banco")
+}
@@ -0,0 +1,122 @@
+package quiz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Estrutura para requisição ao Ollama
+type OllamaRequest struct {
+	Model   string         `json:"model"`
+	Prompt  string         `json:"prompt"`
+	Stream  bool           `json:"stream"`
+	Format  any            `json:"format,omitempty"` // JSON Schema da resposta
+	Options *OllamaOptions `json:"options,omitempty"`
+}
+
+// OllamaOptions são os parâmetros de amostragem aceitos pelo Ollama.
+type OllamaOptions struct {
+	Temperature float64 `json:"temperature"`
+	Seed        int     `json:"seed,omitempty"` // 0 deixa o Ollama sortear
+}
+
+type OllamaResponse struct {
+	Response string `json:"response"`
+	Done     bool   `json:"done"`
+}
+
+// GeradorOllama gera questões com a API /api/generate do Ollama, pedindo a
+// resposta no formato de esquemaQuestao (saída estruturada).
+type GeradorOllama struct {
+	URL         string // endereço base ou caminho completo de /api/generate
+	Modelo      string
+	Temperatura float64
+	Semente     int
+	Tentativas  int // tentativas por questão, incluindo os reparos
+	Cliente     *http.Client
+}
+
+func (g *GeradorOllama) Nome() string {
+	return fmt.Sprintf("Ollama (%s)", g.Modelo)
+}
+
+// endpoint aceita tanto o endereço base quanto o caminho completo de /api/generate.
+func (g *GeradorOllama) endpoint() string {
+	url := strings.TrimRight(g.URL, "/")
+	if strings.HasSuffix(url, "/api/generate") {
+		return url
+	}
+	return url + "/api/generate"
+}
+
+func (g *GeradorOllama) Disponivel(ctx context.Context) error {
+	_, err := g.enviar(ctx, OllamaRequest{Model: g.Modelo, Prompt: "test"})
+	return err
+}
+
+// Gerar pede a questão ao Ollama e, se a resposta não passar na validação,
+// devolve o motivo ao modelo e tenta de novo, até g.Tentativas vezes.
+func (g *GeradorOllama) Gerar(ctx context.Context, pedido PedidoQuestao) (*Questao, error) {
+	return gerarComReparo(ctx, pedido, g.Tentativas, func(ctx context.Context, prompt string) (string, error) {
+		return g.enviar(ctx, OllamaRequest{
+			Model:   g.Modelo,
+			Prompt:  prompt,
+			Format:  esquemaQuestao(tipoPedido(pedido)),
+			Options: &OllamaOptions{Temperature: g.Temperatura, Seed: g.Semente},
+		})
+	}, decodificarQuestao)
+}
+
+// Responder pede ao modelo a resposta de uma questão, sem mostrar a correta.
+func (g *GeradorOllama) Responder(ctx context.Context, questao Questao) ([]string, error) {
+	resposta, err := g.enviar(ctx, OllamaRequest{
+		Model:   g.Modelo,
+		Prompt:  montarPromptResposta(questao),
+		Format:  esquemaResposta(questao),
+		Options: &OllamaOptions{Temperature: g.Temperatura, Seed: g.Semente},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return interpretarResposta(questao, resposta)
+}
+
+func (g *GeradorOllama) enviar(ctx context.Context, reqBody OllamaRequest) (string, error) {
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("erro ao serializar requisição: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.endpoint(), bytes.NewReader(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("erro ao criar requisição: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.Cliente.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("erro ao conectar com Ollama: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler resposta: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Ollama respondeu com status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var ollamaResp OllamaResponse
+	if err := json.Unmarshal(body, &ollamaResp); err != nil {
+		return "", fmt.Errorf("erro ao decodificar resposta do Ollama: %v", err)
+	}
+
+	return ollamaResp.Response, nil
+}
@@ -0,0 +1,161 @@
+package quiz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"quiz_go/internal/ollamafalso"
+)
+
+// questaoValida é uma questão de múltipla escolha no formato pedido ao modelo.
+func questaoValida() map[string]any {
+	return map[string]any{
+		"tipo":        TipoMultiplaEscolha,
+		"questao":     "Qual função retorna o tamanho de uma slice?",
+		"opcoes":      []string{"len", "cap", "size", "count"},
+		"resposta":    "len",
+		"explicacao":  "len retorna o número de elementos; cap, a capacidade.",
+		"dificuldade": "facil",
+		"categoria":   "sintaxe",
+	}
+}
+
+func novoGeradorOllama(s *ollamafalso.Servidor) *GeradorOllama {
+	return &GeradorOllama{URL: s.URL, Modelo: "modelo-teste", Tentativas: 3, Cliente: s.Client()}
+}
+
+var pedidoTeste = PedidoQuestao{Dificuldade: "facil", Categoria: "sintaxe"}
+
+func TestGeradorOllamaRespostasGravadas(t *testing.T) {
+	s := ollamafalso.Novo(t, ollamafalso.Gravacao(t, "testdata/ollama_gravado.json")...)
+	g := novoGeradorOllama(s)
+
+	esperadas := []string{"const", "Retorna o valor zero imediatamente"}
+	for _, esperada := range esperadas {
+		questao, err := g.Gerar(context.Background(), pedidoTeste)
+		if err != nil {
+			t.Fatalf("Gerar: %v", err)
+		}
+		if questao.Resposta != esperada {
+			t.Errorf("resposta = %q, esperado %q", questao.Resposta, esperada)
+		}
+		if questao.ID < PrimeiroIDGerado {
+			t.Errorf("ID %d fora da faixa das questões geradas", questao.ID)
+		}
+	}
+
+	for _, req := range s.Requisicoes() {
+		if req.Modelo != "modelo-teste" {
+			t.Errorf("modelo = %q, esperado modelo-teste", req.Modelo)
+		}
+		if !strings.Contains(string(req.Formato), `"opcoes"`) {
+			t.Errorf("requisição sem o esquema da questão: %s", req.Formato)
+		}
+	}
+}
+
+func TestGeradorOllamaReparaRespostaGravada(t *testing.T) {
+	s := ollamafalso.Novo(t, ollamafalso.Gravacao(t, "testdata/ollama_gravado_reparo.json")...)
+
+	questao, err := novoGeradorOllama(s).Gerar(context.Background(), pedidoTeste)
+	if err != nil {
+		t.Fatalf("Gerar: %v", err)
+	}
+	if questao.Resposta != "nil" {
+		t.Errorf("resposta = %q, esperado nil", questao.Resposta)
+	}
+
+	reqs := s.Requisicoes()
+	if len(reqs) != 2 {
+		t.Fatalf("%d requisições, esperado 2", len(reqs))
+	}
+	if !strings.Contains(reqs[1].Prompt, "resposta 'Um map nil' não encontrada nas opções") {
+		t.Errorf("o reparo deveria trazer o motivo da rejeição:\n%s", reqs[1].Prompt)
+	}
+}
+
+func TestGeradorOllamaReparaRespostaInvalida(t *testing.T) {
+	// Uma resposta cortada pelo limite de tokens não chega a ser JSON válido.
+	s := ollamafalso.Novo(t,
+		ollamafalso.Resposta{Texto: `{"tipo": "multipla_escolha", "questao": "Qual fun`},
+		ollamafalso.JSON(questaoValida()),
+	)
+
+	questao, err := novoGeradorOllama(s).Gerar(context.Background(), pedidoTeste)
+	if err != nil {
+		t.Fatalf("Gerar: %v", err)
+	}
+	if questao.Resposta != "len" {
+		t.Errorf("resposta = %q, esperado len", questao.Resposta)
+	}
+
+	reqs := s.Requisicoes()
+	if len(reqs) != 2 {
+		t.Fatalf("%d requisições, esperado 2", len(reqs))
+	}
+	if !strings.Contains(reqs[1].Prompt, `"questao": "Qual fun`) || !strings.Contains(reqs[1].Prompt, "erro ao decodificar questão gerada") {
+		t.Errorf("o reparo deveria trazer a resposta rejeitada e o motivo:\n%s", reqs[1].Prompt)
+	}
+}
+
+func TestGeradorOllamaDesisteDepoisDasTentativas(t *testing.T) {
+	invalida := questaoValida()
+	invalida["opcoes"] = []string{"len", "cap", "size"}
+	s := ollamafalso.Novo(t, ollamafalso.JSON(invalida), ollamafalso.JSON(invalida), ollamafalso.JSON(invalida))
+
+	_, err := novoGeradorOllama(s).Gerar(context.Background(), pedidoTeste)
+	var erroGeracao *ErroGeracao
+	if !errors.As(err, &erroGeracao) {
+		t.Fatalf("erro = %v, esperado *ErroGeracao", err)
+	}
+	if len(erroGeracao.Falhas) != 3 {
+		t.Errorf("%d falhas, esperado 3", len(erroGeracao.Falhas))
+	}
+	if !strings.Contains(erroGeracao.Falhas[0].Motivo, "exatamente 4 opções") {
+		t.Errorf("motivo = %q", erroGeracao.Falhas[0].Motivo)
+	}
+}
+
+func TestGeradorOllamaErrosDeComunicacaoNaoSaoRepetidos(t *testing.T) {
+	casos := []struct {
+		nome     string
+		resposta ollamafalso.Resposta
+		erro     string
+	}{
+		{"status 500", ollamafalso.Erro(500, "modelo não carregado"), "status 500: modelo não carregado"},
+		{"corpo malformado", ollamafalso.Malformado(`{"response": "trunc`), "erro ao decodificar resposta do Ollama"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			s := ollamafalso.Novo(t, c.resposta, ollamafalso.JSON(questaoValida()))
+
+			_, err := novoGeradorOllama(s).Gerar(context.Background(), pedidoTeste)
+			if err == nil || !strings.Contains(err.Error(), c.erro) {
+				t.Fatalf("erro = %v, esperado conter %q", err, c.erro)
+			}
+			if s.Pendentes() != 1 {
+				t.Errorf("a requisição não deveria ter sido repetida")
+			}
+		})
+	}
+}
+
+func TestGeradorOllamaRespeitaTempoLimite(t *testing.T) {
+	s := ollamafalso.Novo(t, ollamafalso.Lento(10*time.Second, ollamafalso.JSON(questaoValida())))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	inicio := time.Now()
+	_, err := novoGeradorOllama(s).Gerar(ctx, pedidoTeste)
+	if err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Fatalf("erro = %v, esperado o tempo limite do contexto", err)
+	}
+	if time.Since(inicio) > 5*time.Second {
+		t.Errorf("Gerar esperou a resposta lenta em vez de respeitar o tempo limite")
+	}
+}
@@ -0,0 +1,162 @@
+package quiz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Estruturas do endpoint /v1/chat/completions, compatível com OpenAI,
+// LM Studio, vLLM e o servidor do llama.cpp.
+type MensagemChat struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type OpenAIRequest struct {
+	Model          string         `json:"model"`
+	Messages       []MensagemChat `json:"messages"`
+	Temperature    float64        `json:"temperature"`
+	Stream         bool           `json:"stream"`
+	ResponseFormat *FormatoOpenAI `json:"response_format,omitempty"`
+}
+
+// FormatoOpenAI pede a resposta no formato de um JSON Schema (saída
+// estruturada).
+type FormatoOpenAI struct {
+	Type       string `json:"type"` // "json_schema"
+	JSONSchema struct {
+		Name   string         `json:"name"`
+		Schema map[string]any `json:"schema"`
+	} `json:"json_schema"`
+}
+
+func formatoOpenAI(nome string, esquema map[string]any) *FormatoOpenAI {
+	f := &FormatoOpenAI{Type: "json_schema"}
+	f.JSONSchema.Name = nome
+	f.JSONSchema.Schema = esquema
+	return f
+}
+
+type OpenAIResponse struct {
+	Choices []struct {
+		Message MensagemChat `json:"message"`
+	} `json:"choices"`
+}
+
+// GeradorOpenAI gera questões em qualquer servidor compatível com a API de
+// chat da OpenAI, pedindo a resposta no formato de esquemaQuestao (saída
+// estruturada).
+type GeradorOpenAI struct {
+	URL         string // endereço base, com ou sem o sufixo /v1
+	Modelo      string
+	ChaveAPI    string // opcional; enviada como Bearer token
+	Temperatura float64
+	Tentativas  int // tentativas por questão, incluindo os reparos
+	Cliente     *http.Client
+}
+
+func (g *GeradorOpenAI) Nome() string {
+	return fmt.Sprintf("OpenAI compatível (%s)", g.Modelo)
+}
+
+func (g *GeradorOpenAI) endpoint(caminho string) string {
+	base := strings.TrimSuffix(strings.TrimRight(g.URL, "/"), "/v1")
+	return base + "/v1" + caminho
+}
+
+func (g *GeradorOpenAI) Disponivel(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.endpoint("/models"), nil)
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição: %v", err)
+	}
+	g.autenticar(req)
+
+	resp, err := g.Cliente.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao conectar com %s: %v", g.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s respondeu com status %d", g.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// Gerar pede a questão ao servidor e, se a resposta não passar na validação,
+// devolve o motivo ao modelo e tenta de novo, até g.Tentativas vezes.
+func (g *GeradorOpenAI) Gerar(ctx context.Context, pedido PedidoQuestao) (*Questao, error) {
+	formato := formatoOpenAI("questao", esquemaQuestao(tipoPedido(pedido)))
+	return gerarComReparo(ctx, pedido, g.Tentativas, func(ctx context.Context, prompt string) (string, error) {
+		return g.enviar(ctx, prompt, formato)
+	}, decodificarQuestao)
+}
+
+// Responder pede ao modelo a resposta de uma questão, sem mostrar a correta.
+func (g *GeradorOpenAI) Responder(ctx context.Context, questao Questao) ([]string, error) {
+	resposta, err := g.enviar(ctx, montarPromptResposta(questao), formatoOpenAI("resposta", esquemaResposta(questao)))
+	if err != nil {
+		return nil, err
+	}
+	return interpretarResposta(questao, resposta)
+}
+
+func (g *GeradorOpenAI) enviar(ctx context.Context, prompt string, formato *FormatoOpenAI) (string, error) {
+	reqBody := OpenAIRequest{
+		Model: g.Modelo,
+		Messages: []MensagemChat{
+			{Role: "system", Content: "Você é um professor de Go que cria questões de quiz e responde apenas com JSON."},
+			{Role: "user", Content: prompt},
+		},
+		Temperature:    g.Temperatura,
+		ResponseFormat: formato,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("erro ao serializar requisição: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.endpoint("/chat/completions"), bytes.NewReader(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("erro ao criar requisição: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	g.autenticar(req)
+
+	resp, err := g.Cliente.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("erro ao conectar com %s: %v", g.URL, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler resposta: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s respondeu com status %d: %s", g.URL, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var chatResp OpenAIResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return "", fmt.Errorf("erro ao decodificar resposta: %v", err)
+	}
+	if len(chatResp.Choices) == 0 {
+		return "", fmt.Errorf("resposta sem escolhas")
+	}
+
+	return chatResp.Choices[0].Message.Content, nil
+}
+
+func (g *GeradorOpenAI) autenticar(req *http.Request) {
+	if g.ChaveAPI != "" {
+		req.Header.Set("Authorization", "Bearer "+g.ChaveAPI)
+	}
+}
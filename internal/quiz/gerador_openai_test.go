@@ -0,0 +1,46 @@
+package quiz
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"quiz_go/internal/ollamafalso"
+)
+
+func novoGeradorOpenAI(s *ollamafalso.Servidor) *GeradorOpenAI {
+	return &GeradorOpenAI{URL: s.URL + "/v1", Modelo: "modelo-teste", Tentativas: 3, Cliente: s.Client()}
+}
+
+func TestGeradorOpenAIReparaRespostaInvalida(t *testing.T) {
+	// Texto em volta do JSON não é aceito: a saída estruturada devolve só o
+	// objeto.
+	comTexto := ollamafalso.JSON(questaoValida())
+	comTexto.Texto = "Aqui está a questão: " + comTexto.Texto
+	s := ollamafalso.Novo(t, comTexto, ollamafalso.JSON(questaoValida()))
+	g := novoGeradorOpenAI(s)
+
+	if err := g.Disponivel(context.Background()); err != nil {
+		t.Fatalf("Disponivel: %v", err)
+	}
+	questao, err := g.Gerar(context.Background(), pedidoTeste)
+	if err != nil {
+		t.Fatalf("Gerar: %v", err)
+	}
+	if questao.Resposta != "len" {
+		t.Errorf("resposta = %q, esperado len", questao.Resposta)
+	}
+
+	reqs := s.Requisicoes()
+	if len(reqs) != 2 {
+		t.Fatalf("%d requisições, esperado 2", len(reqs))
+	}
+	for _, req := range reqs {
+		if !strings.Contains(string(req.Formato), `"type":"json_schema"`) || !strings.Contains(string(req.Formato), `"opcoes"`) {
+			t.Errorf("requisição sem o esquema da questão: %s", req.Formato)
+		}
+	}
+	if !strings.Contains(reqs[1].Prompt, "erro ao decodificar questão gerada") {
+		t.Errorf("o reparo deveria trazer o motivo da rejeição:\n%s", reqs[1].Prompt)
+	}
+}
@@ -0,0 +1,101 @@
+package quiz
+
+import "time"
+
+// Apresentador mostra ao jogador o andamento de um quiz conduzido por Jogar.
+type Apresentador interface {
+	// Inicio abre o quiz. duracaoProva é zero fora do modo Prova.
+	Inicio(total int, duracaoProva time.Duration)
+
+	// Aguardando é chamado quando a próxima questão ainda está sendo
+	// gerada. A função retornada é chamada quando ela fica pronta.
+	Aguardando() (pronta func())
+
+	// Questao mostra a questão antes de a resposta ser pedida.
+	Questao(questao Questao, numero, total int)
+
+	// ErroResposta informa que a resposta não pôde ser lida. A questão é
+	// descartada.
+	ErroResposta(err error)
+
+	Resultado(r Resultado)
+	Progresso(numero, total, acertos int)
+
+	// FimProva informa que o tempo da prova acabou, com semResposta
+	// questões não respondidas.
+	FimProva(semResposta int)
+
+	// Interrompido informa que o jogador saiu antes do fim do quiz.
+	Interrompido()
+
+	Resumo(r Resumo)
+}
+
+// FonteRespostas lê as respostas do jogador. Prazos zerados não se aplicam.
+type FonteRespostas interface {
+	// Responder pede a resposta da questão até o prazo e retorna as opções
+	// marcadas ou o texto digitado. esgotado indica que o prazo acabou
+	// antes da resposta.
+	Responder(questao Questao, prazo time.Time) (escolhidas []string, esgotado bool, err error)
+
+	// Continuar pergunta se o jogador quer ir para a próxima questão. No
+	// modo Prova o tempo continua correndo, e esgotado indica que a prova
+	// acabou durante a pergunta.
+	Continuar(prazo time.Time) (continuar, esgotado bool)
+}
+
+// Jogar conduz a sessão até o fim: mostra cada questão, lê e corrige a
+// resposta e, terminado o quiz, atualiza as estatísticas e mostra o resumo.
+func Jogar(s *Sessao, tela Apresentador, fonte FonteRespostas) {
+	tela.Inicio(s.Total(), s.DuracaoProva())
+
+	for {
+		questao, ok, pronta := s.ProximaPronta()
+		if !pronta {
+			feito := tela.Aguardando()
+			questao, ok = s.Proxima()
+			feito()
+		}
+		if !ok {
+			break
+		}
+
+		tela.Questao(questao, s.Numero(), s.Total())
+		escolhidas, esgotado, err := fonte.Responder(questao, s.Prazo())
+		if err != nil {
+			tela.ErroResposta(err)
+			s.Descartar()
+			continue
+		}
+
+		var resultado Resultado
+		if esgotado {
+			resultado, _ = s.EsgotarTempo()
+		} else {
+			resultado, _ = s.Responder(escolhidas)
+		}
+		tela.Resultado(resultado)
+
+		if s.ProvaEncerrada() {
+			tela.FimProva(s.Restantes())
+			break
+		}
+		if s.Restantes() == 0 {
+			continue
+		}
+
+		tela.Progresso(s.Numero(), s.Total(), resultado.Acertos)
+		continuar, esgotado := fonte.Continuar(s.FimProva())
+		if esgotado || s.ProvaEncerrada() {
+			tela.FimProva(s.Restantes())
+			break
+		}
+		if !continuar {
+			tela.Interrompido()
+			s.Interromper()
+			return
+		}
+	}
+
+	tela.Resumo(s.Encerrar())
+}
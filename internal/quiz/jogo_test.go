@@ -0,0 +1,230 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"quiz_go/internal/ollamafalso"
+	"quiz_go/internal/stats"
+)
+
+// roteiro responde às questões com as respostas combinadas, na ordem, e
+// anota o que o quiz mostrou. Implementa Apresentador e FonteRespostas.
+type roteiro struct {
+	respostas  []respostaRoteiro
+	continuar  int // quantas vezes aceita continuar; negativo para sempre
+	mostradas  []int
+	resultados []Resultado
+	resumo     *Resumo
+	eventos    []string
+}
+
+type respostaRoteiro struct {
+	escolhida string
+	esgotado  bool
+	err       error
+}
+
+func (r *roteiro) Inicio(total int, duracaoProva time.Duration) {
+	r.eventos = append(r.eventos, fmt.Sprintf("inicio %d", total))
+}
+
+func (r *roteiro) Aguardando() func() { return func() {} }
+
+func (r *roteiro) Questao(questao Questao, numero, total int) {
+	r.mostradas = append(r.mostradas, questao.ID)
+}
+
+func (r *roteiro) ErroResposta(err error)               { r.eventos = append(r.eventos, "erro") }
+func (r *roteiro) Resultado(res Resultado)              { r.resultados = append(r.resultados, res) }
+func (r *roteiro) Progresso(numero, total, acertos int) {}
+func (r *roteiro) FimProva(semResposta int)             { r.eventos = append(r.eventos, "fim da prova") }
+func (r *roteiro) Interrompido()                        { r.eventos = append(r.eventos, "interrompido") }
+func (r *roteiro) Resumo(res Resumo)                    { r.resumo = &res }
+
+func (r *roteiro) Responder(questao Questao, prazo time.Time) ([]string, bool, error) {
+	resposta := r.respostas[0]
+	r.respostas = r.respostas[1:]
+	return []string{resposta.escolhida}, resposta.esgotado, resposta.err
+}
+
+func (r *roteiro) Continuar(prazo time.Time) (bool, bool) {
+	if r.continuar == 0 {
+		return false, false
+	}
+	r.continuar--
+	return true, false
+}
+
+func questoesTeste(n int) []Questao {
+	questoes := make([]Questao, n)
+	for i := range questoes {
+		questoes[i] = Questao{
+			ID:          i + 1,
+			Questao:     fmt.Sprintf("Questão %d", i+1),
+			Opcoes:      []string{"a", "b", "c", "d"},
+			Resposta:    "a",
+			Dificuldade: "facil",
+			Categoria:   "sintaxe",
+		}
+	}
+	return questoes
+}
+
+// sessaoTeste cria uma sessão com n questoesTeste.
+func sessaoTeste(q *Quiz, n int) *Sessao {
+	canal, total := canalCompleto(questoesTeste(n))
+	return q.novaSessao(preparo{questoes: canal, total: total})
+}
+
+func novoQuizOffline(t *testing.T) *Quiz {
+	t.Helper()
+	s := ollamafalso.Novo(t)
+	s.Close()
+	return novoQuizTeste(t, s)
+}
+
+func TestJogarCorrigeERegistraAsRespostas(t *testing.T) {
+	q := novoQuizOffline(t)
+	r := &roteiro{
+		continuar: -1,
+		respostas: []respostaRoteiro{
+			{escolhida: "a"},
+			{escolhida: "b"},
+			{err: errors.New("entrada fechada")},
+			{esgotado: true},
+		},
+	}
+
+	Jogar(sessaoTeste(q, 4), r, r)
+
+	if r.resumo == nil {
+		t.Fatal("o resumo não foi mostrado")
+	}
+	// A questão cuja resposta não foi lida fica fora das respondidas.
+	if r.resumo.Acertos != 1 || r.resumo.Respondidas != 3 || r.resumo.SemTempo() != 1 {
+		t.Errorf("resumo = %d acertos, %d respondidas, %d sem tempo; esperado 1, 3 e 1",
+			r.resumo.Acertos, r.resumo.Respondidas, r.resumo.SemTempo())
+	}
+	if len(r.resultados) != 3 || !r.resultados[0].Correta || r.resultados[1].Correta || !r.resultados[2].Esgotado {
+		t.Errorf("resultados inesperados: %+v", r.resultados)
+	}
+
+	if q.stats.TotalQuizzes != 1 || q.stats.TotalAcertos != 1 || q.stats.TotalQuestoes != 3 {
+		t.Errorf("estatísticas = %d quizzes, %d acertos e %d questões, esperado 1, 1 e 3", q.stats.TotalQuizzes, q.stats.TotalAcertos, q.stats.TotalQuestoes)
+	}
+	if n := len(q.stats.Respostas); n != 3 {
+		t.Errorf("%d respostas no histórico, esperado 3 (a não lida fica de fora)", n)
+	}
+	salvas, err := stats.CarregarEstatisticas(q.statsFile)
+	if err != nil || salvas.TotalQuizzes != 1 {
+		t.Errorf("estatísticas salvas = %+v, %v", salvas, err)
+	}
+}
+
+func TestQuizSemRespostasLidasNaoContaNosTotais(t *testing.T) {
+	q := novoQuizOffline(t)
+	ilegivel := respostaRoteiro{err: errors.New("entrada fechada")}
+	r := &roteiro{continuar: -1, respostas: []respostaRoteiro{ilegivel, ilegivel}}
+
+	Jogar(sessaoTeste(q, 2), r, r)
+
+	if r.resumo == nil || r.resumo.Respondidas != 0 || r.resumo.ErroEstatisticas != nil {
+		t.Fatalf("resumo = %+v", r.resumo)
+	}
+	salvas, err := stats.CarregarEstatisticas(q.statsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if salvas.TotalQuizzes != 0 || salvas.MediaPercentual != 0 {
+		t.Errorf("estatísticas salvas = %d quizzes, média %v; esperado 0 e 0", salvas.TotalQuizzes, salvas.MediaPercentual)
+	}
+}
+
+func TestResumoInformaErroAoSalvarEstatisticas(t *testing.T) {
+	q := novoQuizOffline(t)
+	q.statsFile = filepath.Join(t.TempDir(), "sem_diretorio", "stats.json")
+	r := &roteiro{continuar: -1, respostas: []respostaRoteiro{{escolhida: "a"}}}
+
+	Jogar(sessaoTeste(q, 1), r, r)
+
+	if r.resumo == nil || r.resumo.ErroEstatisticas == nil {
+		t.Errorf("o resumo deveria trazer o erro ao salvar as estatísticas: %+v", r.resumo)
+	}
+}
+
+func TestJogarInterrompidoNaoContaNosTotais(t *testing.T) {
+	q := novoQuizOffline(t)
+	r := &roteiro{respostas: []respostaRoteiro{{escolhida: "a"}}}
+
+	Jogar(sessaoTeste(q, 3), r, r)
+
+	if r.resumo != nil {
+		t.Error("o quiz interrompido não deveria mostrar o resumo")
+	}
+	if len(r.mostradas) != 1 || r.eventos[len(r.eventos)-1] != "interrompido" {
+		t.Errorf("mostradas = %v, eventos = %v", r.mostradas, r.eventos)
+	}
+	if q.stats.TotalQuizzes != 0 || len(q.stats.Respostas) != 1 {
+		t.Errorf("estatísticas = %d quizzes e %d respostas, esperado 0 e 1", q.stats.TotalQuizzes, len(q.stats.Respostas))
+	}
+}
+
+func TestProvaEncerradaContaAsQuestoesSemResposta(t *testing.T) {
+	q := novoQuizOffline(t)
+	canal, total := canalCompleto(questoesTeste(5))
+	s := q.novaSessao(preparo{modo: ModoProva, questoes: canal, total: total, prova: time.Hour})
+
+	s.Proxima()
+	if _, err := s.Responder([]string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	s.cronometro.fimProva = time.Now()
+
+	// Um acerto em uma prova de 5 questões vale 20%, não 100%.
+	r := s.Encerrar()
+	if r.Respondidas != 5 || r.SemResposta != 4 || r.Percentual() != 20 {
+		t.Errorf("resumo = %d respondidas, %d sem resposta, %.0f%%; esperado 5, 4 e 20%%",
+			r.Respondidas, r.SemResposta, r.Percentual())
+	}
+	if p := q.stats.Partidas[len(q.stats.Partidas)-1]; p.Respondidas != r.Respondidas || p.Acertos != 1 {
+		t.Errorf("partida = %+v, esperado as mesmas %d respondidas do resumo", p, r.Respondidas)
+	}
+	if q.stats.TotalQuestoes != 5 {
+		t.Errorf("total de questões = %d, esperado 5", q.stats.TotalQuestoes)
+	}
+}
+
+func TestSessaoEntregaAMesmaQuestaoAteResponder(t *testing.T) {
+	q := novoQuizOffline(t)
+	s := sessaoTeste(q, 2)
+
+	if _, err := s.Responder([]string{"a"}); !errors.Is(err, ErrSemQuestao) {
+		t.Errorf("erro = %v, esperado ErrSemQuestao", err)
+	}
+
+	primeira, _ := s.Proxima()
+	repetida, _ := s.Proxima()
+	if primeira.ID != repetida.ID || s.Numero() != 1 {
+		t.Errorf("questões %d e %d, número %d; esperado a mesma questão", primeira.ID, repetida.ID, s.Numero())
+	}
+
+	if _, err := s.Responder([]string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Responder([]string{"a"}); !errors.Is(err, ErrSemQuestao) {
+		t.Errorf("a segunda resposta à mesma questão deveria falhar, veio %v", err)
+	}
+
+	segunda, ok := s.Proxima()
+	if !ok || segunda.ID == primeira.ID || s.Restantes() != 0 {
+		t.Errorf("segunda questão = %d (%v), restantes %d", segunda.ID, ok, s.Restantes())
+	}
+	s.Descartar()
+	if _, ok := s.Proxima(); ok {
+		t.Error("não deveria haver uma terceira questão")
+	}
+}
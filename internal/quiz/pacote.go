@@ -0,0 +1,23 @@
+package quiz
+
+import "embed"
+
+// pacoteFS contém o pacote padrão de questões, embutido no binário.
+//
+//go:embed pacote/*.yaml
+var pacoteFS embed.FS
+
+// PrimeiroIDPacote é o menor ID usado pelo pacote embutido; questões locais
+// devem usar IDs menores.
+const PrimeiroIDPacote = 101001
+
+// Categorias lista as categorias cobertas pelo pacote embutido e usadas na geração com IA.
+var Categorias = []string{"sintaxe", "tipos", "concorrencia", "bibliotecas", "interfaces", "erros", "estruturas", "Goroutines", "testes", "garbage collector", "banco de dados", "segurança e boas práticas"}
+
+// Dificuldades lista os níveis aceitos no campo Dificuldade.
+var Dificuldades = []string{"facil", "medio", "dificil"}
+
+// CarregarPacote retorna as questões do pacote embutido no binário.
+func CarregarPacote() ([]Questao, error) {
+	return CarregarBancoFS(pacoteFS, "pacote")
+}
@@ -0,0 +1,63 @@
+package quiz
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPacoteEmbutidoValido(t *testing.T) {
+	questoes, err := CarregarPacote()
+	if err != nil {
+		t.Fatalf("pacote embutido inválido:\n%v", err)
+	}
+
+	if len(questoes) < 200 {
+		t.Errorf("pacote embutido tem %d questões, esperado pelo menos 200", len(questoes))
+	}
+
+	categorias := make(map[string]bool)
+	for _, categoria := range Categorias {
+		categorias[categoria] = true
+	}
+
+	cobertura := make(map[[2]string]int)
+	for _, questao := range questoes {
+		if !categorias[questao.Categoria] {
+			t.Errorf("questão %d usa categoria desconhecida %q", questao.ID, questao.Categoria)
+		}
+		if questao.Explicacao == "" {
+			t.Errorf("questão %d sem explicação", questao.ID)
+		}
+		cobertura[[2]string{questao.Categoria, questao.Dificuldade}]++
+	}
+
+	for _, categoria := range Categorias {
+		for _, dificuldade := range Dificuldades {
+			if cobertura[[2]string{categoria, dificuldade}] == 0 {
+				t.Errorf("nenhuma questão para %s/%s", categoria, dificuldade)
+			}
+		}
+	}
+}
+
+func TestPacoteSaidasConferem(t *testing.T) {
+	if testing.Short() || !GoDisponivel() || !IsolamentoDisponivel() {
+		t.Skip("precisa da ferramenta go e do isolamento de rede para executar o código")
+	}
+
+	questoes, err := CarregarPacote()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executor := &ExecutorGo{Timeout: time.Minute}
+	for _, questao := range questoes {
+		if questao.TipoQuestao() != TipoSaidaCodigo {
+			continue
+		}
+		if err := verificarSaida(context.Background(), executor, &questao, false); err != nil {
+			t.Errorf("questão %d: %v", questao.ID, err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package quiz
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+const (
+	// categoriasFoco é quantas categorias, das piores, o modo usa.
+	categoriasFoco = 3
+
+	// conceitosPorPedido é quantas explicações de erros recentes vão no prompt.
+	conceitosPorPedido = 3
+
+	// tamanhoConceito limita cada explicação enviada no prompt.
+	tamanhoConceito = 240
+)
+
+// pesoCategoria é a taxa de erro na categoria com suavização de Laplace:
+// categorias nunca respondidas ficam com 0,5, no meio da escala.
+type pesoCategoria struct {
+	categoria string
+	peso      float64
+}
+
+// pesosPontosFracos calcula o peso de cada categoria de Categorias a partir
+// do histórico e retorna as categoriasFoco de maior taxa de erro.
+func (q *Quiz) pesosPontosFracos() []pesoCategoria {
+	respondidas := make(map[string]int)
+	erros := make(map[string]int)
+	for _, r := range q.stats.Respostas {
+		categoria, ok := categoriaConhecida(r.Categoria)
+		if !ok {
+			continue
+		}
+		respondidas[categoria]++
+		if !r.Correta {
+			erros[categoria]++
+		}
+	}
+
+	pesos := make([]pesoCategoria, len(Categorias))
+	for i, categoria := range Categorias {
+		peso := float64(erros[categoria]+1) / float64(respondidas[categoria]+2)
+		pesos[i] = pesoCategoria{categoria: categoria, peso: peso}
+	}
+
+	// Ordena pelo peso; o embaralhamento antes desempata ao acaso.
+	rand.Shuffle(len(pesos), func(i, j int) { pesos[i], pesos[j] = pesos[j], pesos[i] })
+	sort.SliceStable(pesos, func(i, j int) bool { return pesos[i].peso > pesos[j].peso })
+	return pesos[:min(categoriasFoco, len(pesos))]
+}
+
+// pedidosPontosFracos monta os pedidos do modo "Foco nos pontos fracos":
+// cada questão sai de uma das categorias com mais erros, sorteada com
+// probabilidade proporcional à taxa de erro, e leva as explicações das
+// questões erradas recentemente nessa categoria.
+func (q *Quiz) pedidosPontosFracos(quantidade int, dificuldade string) []PedidoQuestao {
+	pesos := q.pesosPontosFracos()
+	total := 0.0
+	for _, p := range pesos {
+		total += p.peso
+	}
+
+	conceitos := q.conceitosErrados()
+	pedidos := montarPedidos(quantidade, dificuldade, "", "")
+	for i := range pedidos {
+		sorteio := rand.Float64() * total
+		categoria := pesos[len(pesos)-1].categoria
+		for _, p := range pesos {
+			if sorteio < p.peso {
+				categoria = p.categoria
+				break
+			}
+			sorteio -= p.peso
+		}
+		pedidos[i].Categoria = categoria
+		pedidos[i].Conceitos = conceitos[categoria]
+	}
+	return pedidos
+}
+
+// conceitosErrados junta, por categoria, as explicações das questões erradas
+// mais recentes que ainda estão no banco ou no acervo.
+func (q *Quiz) conceitosErrados() map[string][]string {
+	porID := make(map[int]Questao, len(q.questoes))
+	for _, questao := range q.questoes {
+		porID[questao.ID] = questao
+	}
+	if q.acervo != nil {
+		for _, questao := range q.acervo.Questoes() {
+			porID[questao.ID] = questao
+		}
+	}
+
+	conceitos := make(map[string][]string)
+	vistos := make(map[int]bool)
+	for i := len(q.stats.Respostas) - 1; i >= 0; i-- {
+		r := q.stats.Respostas[i]
+		categoria, ok := categoriaConhecida(r.Categoria)
+		if r.Correta || !ok || vistos[r.QuestaoID] || len(conceitos[categoria]) >= conceitosPorPedido {
+			continue
+		}
+		questao, ok := porID[r.QuestaoID]
+		if !ok || questao.Explicacao == "" {
+			continue
+		}
+		vistos[r.QuestaoID] = true
+		conceitos[categoria] = append(conceitos[categoria], resumir(questao.Explicacao, tamanhoConceito))
+	}
+	return conceitos
+}
+
+// categoriaConhecida devolve o nome de Categorias que corresponde a
+// categoria, ignorando maiúsculas.
+func categoriaConhecida(categoria string) (string, bool) {
+	for _, c := range Categorias {
+		if strings.EqualFold(c, categoria) {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+func resumir(texto string, limite int) string {
+	texto = strings.Join(strings.Fields(texto), " ")
+	runas := []rune(texto)
+	if len(runas) <= limite {
+		return texto
+	}
+	return strings.TrimSpace(string(runas[:limite])) + "..."
+}
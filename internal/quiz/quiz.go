@@ -1,98 +1,93 @@
 package quiz
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
+	"maps"
 	"math/rand"
 	"net/http"
+	"os"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"quiz_go/internal/config"
 	"quiz_go/internal/ui"
 	"quiz_go/internal/stats"
-	"github.com/pterm/pterm"
-
-	"github.com/AlecAivazis/survey/v2"
 )
 
 type Questao struct {
-	ID          int      `json:"id"`
-	Questao     string   `json:"questao"`
-	Opcoes      []string `json:"opcoes"`
-	Resposta    string   `json:"resposta"`
-	Explicacao  string   `json:"explicacao"`
-	Dificuldade string   `json:"dificuldade"` // "facil", "medio", "dificil"
-	Categoria   string   `json:"categoria"`   // "sintaxe", "tipos", "concorrencia", etc.
+	ID           int      `json:"id" yaml:"id"`
+	Tipo         string   `json:"tipo,omitempty" yaml:"tipo,omitempty"` // veja Tipos; vazio é múltipla escolha
+	Questao      string   `json:"questao" yaml:"questao"`
+	Codigo       string   `json:"codigo,omitempty" yaml:"codigo,omitempty"`
+	Opcoes       []string `json:"opcoes" yaml:"opcoes"`
+	Resposta     string   `json:"resposta" yaml:"resposta"`
+	Respostas    []string `json:"respostas,omitempty" yaml:"respostas,omitempty"`       // múltiplas respostas
+	Alternativas []string `json:"alternativas,omitempty" yaml:"alternativas,omitempty"` // outras respostas digitadas aceitas
+	Explicacao   string   `json:"explicacao" yaml:"explicacao"`
+	Dificuldade  string   `json:"dificuldade" yaml:"dificuldade"`                   // "facil", "medio", "dificil"
+	Categoria    string   `json:"categoria" yaml:"categoria"`                       // "sintaxe", "tipos", "concorrencia", etc.
+	Verificada   bool     `json:"verificada,omitempty" yaml:"verificada,omitempty"` // resposta conferida executando Codigo
 }
 
 type Quiz struct {
-	questoes     []Questao
-	stats        stats.Estatisticas
-	statsFile    string
-	ollamaURL    string
-	ollamaModel  string
-	usarOllama   bool
+	questoes        []Questao
+	stats           stats.Estatisticas
+	statsFile       string
+	gerador         GeradorQuestoes
+	acervo          *AcervoGerado
+	autoverificacao *RegistroAutoverificacao
+	usarIA          bool
+	cliente         *http.Client
+	config          config.Config
+
+	// mu protege stats quando há várias sessões ao mesmo tempo, como no
+	// servidor HTTP.
+	mu sync.Mutex
 }
 
-// Estrutura para requisição ao Ollama
-type OllamaRequest struct {
-	Model  string `json:"model"`
-	Prompt string `json:"prompt"`
-	Stream bool   `json:"stream"`
+// Estrutura esperada da resposta da IA para questões
+type QuestaoGerada struct {
+	Tipo         string   `json:"tipo"`
+	Questao      string   `json:"questao"`
+	Codigo       string   `json:"codigo"`
+	Opcoes       []string `json:"opcoes"`
+	Resposta     string   `json:"resposta"`
+	Respostas    []string `json:"respostas"`
+	Alternativas []string `json:"alternativas"`
+	Explicacao   string   `json:"explicacao"`
+	Dificuldade  string   `json:"dificuldade"`
+	Categoria    string   `json:"categoria"`
 }
 
-type OllamaResponse struct {
-	Response string `json:"response"`
-	Done     bool   `json:"done"`
-}
+// BancoPadrao é o diretório lido quando nenhum banco de questões é informado.
+const BancoPadrao = "questoes"
 
-// Estrutura esperada da resposta da IA para questões
-type QuestaoGerada struct {
-	Questao     string   `json:"questao"`
-	Opcoes      []string `json:"opcoes"`
-	Resposta    string   `json:"resposta"`
-	Explicacao  string   `json:"explicacao"`
-	Dificuldade string   `json:"dificuldade"`
-	Categoria   string   `json:"categoria"`
+// NewQuiz cria o quiz com o pacote embutido de questões, somado aos arquivos
+// ou diretórios de cfg.Banco. Sem caminhos, usa o diretório "questoes" se ele existir.
+func NewQuiz(cfg config.Config) *Quiz {
+	return NewQuizComCliente(cfg, http.DefaultClient)
 }
 
-func NewQuiz() *Quiz {
+// NewQuizComCliente cria o quiz como NewQuiz, mas acessando a IA (no
+// endereço da configuração) pelo cliente informado. Os testes usam um
+// cliente e um endereço de servidor falso para nunca acessar a rede.
+func NewQuizComCliente(cfg config.Config, cliente *http.Client) *Quiz {
 	q := &Quiz{
-		statsFile:   "quiz_stats.json",
-		ollamaURL:   "http://localhost:11434/api/generate",
-		ollamaModel: "llama3:8b", // Pode ser alterado conforme o modelo disponível
-		usarOllama:  true,
-		questoes: []Questao{
-			// Questões de fallback caso o Ollama não esteja disponível
-			{
-				ID:          1,
-				Questao:     "Qual palavra-chave define uma função em Go?",
-				Opcoes:      []string{"func", "function", "def", "lambda"},
-				Resposta:    "func",
-				Explicacao:  "Em Go, usamos a palavra-chave 'func' para definir funções. Exemplo: func minhaFuncao() {}",
-				Dificuldade: "facil",
-				Categoria:   "sintaxe",
-			},
-			{
-				ID:          2,
-				Questao:     "Como declarar uma variável em Go?",
-				Opcoes:      []string{"let x = 10", "var x int = 10", "int x = 10", "x := int(10)"},
-				Resposta:    "var x int = 10",
-				Explicacao:  "Go usa 'var' para declaração explícita de variáveis. Também podemos usar := para declaração curta.",
-				Dificuldade: "facil",
-				Categoria:   "tipos",
-			},
-		},
-	}
-
-	// Verificar se o Ollama está disponível
-	if !q.testarConexaoOllama() {
-		fmt.Println(ui.Yellow("⚠️  Ollama não está disponível. Usando questões pré-definidas."))
-		q.usarOllama = false
-	} else {
-		fmt.Println(ui.Green("✅ Ollama conectado! Questões serão geradas dinamicamente."))
+		statsFile: cfg.ArquivoStats,
+		config:    cfg,
+		cliente:   cliente,
+	}
+
+	q.carregarQuestoes(cfg.Banco)
+	q.abrirAcervo()
+	q.configurarGerador()
+	if !q.usarIA {
+		q.usarAcervoOffline()
 	}
 
 	loadedStats, err := stats.CarregarEstatisticas(q.statsFile)
@@ -104,187 +99,251 @@ func NewQuiz() *Quiz {
 	return q
 }
 
-func (q *Quiz) testarConexaoOllama() bool {
-	client := &http.Client{Timeout: 5 * time.Second}
-	
-	reqBody := OllamaRequest{
-		Model:  q.ollamaModel,
-		Prompt: "test",
-		Stream: false,
-	}
-	
-	jsonData, _ := json.Marshal(reqBody)
-	resp, err := client.Post(q.ollamaURL, "application/json", bytes.NewBuffer(jsonData))
+// configurarGerador escolhe o gerador de cfg.Gerador e verifica se ele
+// responde. O gerador estático é o modo offline: usa apenas o banco.
+func (q *Quiz) configurarGerador() {
+	gerador, err := NovoGerador(q.config, q.questoes, q.cliente)
 	if err != nil {
-		return false
+		fmt.Println(ui.Yellow(fmt.Sprintf("⚠️  %v. Usando questões pré-definidas.", err)))
+		q.gerador = NovoGeradorEstatico(q.questoes)
+		return
 	}
-	defer resp.Body.Close()
-	
-	return resp.StatusCode == 200
-}
+	q.gerador = gerador
 
-func (q *Quiz) gerarQuestaoComOllama(dificuldade, categoria string) (*Questao, error) {
-	prompt := fmt.Sprintf(`Gere uma questão de múltipla escolha sobre programação Go com as seguintes especificações:
+	if _, estatico := gerador.(*GeradorEstatico); estatico {
+		fmt.Println(ui.Yellow("📚 Gerador estático configurado. Usando questões pré-definidas."))
+		return
+	}
+	q.gerador = q.autoverificar(q.verificarCodigo(gerador))
 
-Dificuldade: %s
-Categoria: %s
+	ctx, cancel := context.WithTimeout(context.Background(), q.config.TimeoutConexao)
+	defer cancel()
+	if err := gerador.Disponivel(ctx); err != nil {
+		fmt.Println(ui.Yellow(fmt.Sprintf("⚠️  %s não está disponível. Usando questões pré-definidas.", gerador.Nome())))
+		return
+	}
 
-Retorne APENAS um JSON válido no seguinte formato:
-{
-  "questao": "Texto da pergunta aqui",
-  "opcoes": ["opção 1", "opção 2", "opção 3", "opção 4"],
-  "resposta": "resposta correta exata (deve ser uma das opções)",
-  "explicacao": "Explicação detalhada da resposta",
-  "dificuldade": "%s",
-  "categoria": "%s"
+	q.usarIA = true
+	fmt.Println(ui.Green(fmt.Sprintf("✅ %s conectado! Questões serão geradas dinamicamente.", gerador.Nome())))
 }
 
-Requisitos:
-- A questão deve ser sobre Go/Golang
-- Deve ter exatamente 4 opções
-- Uma resposta deve estar correta
-- A explicação deve ser educativa e de simples entendimento
-- Use português brasileiro
-- Não inclua texto adicional, apenas o JSON`, dificuldade, categoria, dificuldade, categoria)
+// verificarCodigo envolve o gerador para executar o código das questões de
+// saída de código antes de usá-las, conforme cfg.VerificacaoCodigo.
+func (q *Quiz) verificarCodigo(gerador GeradorQuestoes) GeradorQuestoes {
+	switch q.config.VerificacaoCodigo {
+	case VerificacaoDesligada:
+		return gerador
+	case VerificacaoCorrigir, VerificacaoRejeitar:
+	default:
+		fmt.Println(ui.Yellow(fmt.Sprintf("⚠️  verificacao_codigo '%s' desconhecida (use %s, %s ou %s). O código das questões não será executado.",
+			q.config.VerificacaoCodigo, VerificacaoCorrigir, VerificacaoRejeitar, VerificacaoDesligada)))
+		return gerador
+	}
 
-	reqBody := OllamaRequest{
-		Model:  q.ollamaModel,
-		Prompt: prompt,
-		Stream: false,
+	if !GoDisponivel() {
+		fmt.Println(ui.Yellow("⚠️  Ferramenta go não encontrada. As respostas das questões de código geradas não serão conferidas."))
+		return gerador
+	}
+	if !IsolamentoDisponivel() {
+		fmt.Println(ui.Yellow("⚠️  Não é possível executar código sem acesso à rede neste sistema. As respostas das questões de código geradas não serão conferidas."))
+		return gerador
+	}
+	return &GeradorVerificado{
+		GeradorQuestoes: gerador,
+		Executor:        &ExecutorGo{Timeout: q.config.TempoVerificacao},
+		Corrigir:        q.config.VerificacaoCodigo == VerificacaoCorrigir,
 	}
+}
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao serializar requisição: %v", err)
+// autoverificar envolve o gerador para que outro modelo responda às questões
+// geradas, quando cfg.Autoverificacao está ligada.
+func (q *Quiz) autoverificar(gerador GeradorQuestoes) GeradorQuestoes {
+	switch q.config.Autoverificacao {
+	case AutoverificacaoDesligada:
+		return gerador
+	case AutoverificacaoLigada:
+	default:
+		fmt.Println(ui.Yellow(fmt.Sprintf("⚠️  autoverificacao '%s' desconhecida (use %s ou %s). As questões geradas não serão conferidas.",
+			q.config.Autoverificacao, AutoverificacaoLigada, AutoverificacaoDesligada)))
+		return gerador
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Post(q.ollamaURL, "application/json", bytes.NewBuffer(jsonData))
+	respondedor, err := NovoRespondedor(q.config, q.cliente)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao conectar com Ollama: %v", err)
+		fmt.Println(ui.Yellow(fmt.Sprintf("⚠️  %v. As questões geradas não serão conferidas.", err)))
+		return gerador
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	if q.config.ArquivoAutoverificacao != "" {
+		registro, err := AbrirRegistroAutoverificacao(q.config.ArquivoAutoverificacao)
+		if err != nil {
+			fmt.Println(ui.Yellow(fmt.Sprintf("⚠️  %v. A concordância desta sessão não será registrada.", err)))
+		}
+		q.autoverificacao = registro
+	}
+	return &GeradorAutoverificado{GeradorQuestoes: gerador, Respondedor: respondedor, Registro: q.autoverificacao}
+}
+
+// abrirAcervo lê as questões geradas em sessões anteriores. Sem arquivo
+// configurado, ou se ele não puder ser lido, as questões geradas não são guardadas.
+func (q *Quiz) abrirAcervo() {
+	if q.config.ArquivoGeradas == "" {
+		return
+	}
+	acervo, err := AbrirAcervo(q.config.ArquivoGeradas)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao ler resposta: %v", err)
+		fmt.Println(ui.Yellow(fmt.Sprintf("⚠️  %v. Questões geradas anteriormente não serão usadas.", err)))
 	}
+	q.acervo = acervo
+}
 
-	var ollamaResp OllamaResponse
-	if err := json.Unmarshal(body, &ollamaResp); err != nil {
-		return nil, fmt.Errorf("erro ao decodificar resposta do Ollama: %v", err)
+// usarAcervoOffline junta ao banco as questões geradas em sessões anteriores.
+func (q *Quiz) usarAcervoOffline() {
+	if q.acervo == nil {
+		return
 	}
 
-	// Tentar extrair JSON da resposta
-	response := strings.TrimSpace(ollamaResp.Response)
-	
-	// Encontrar o JSON na resposta (às vezes a IA adiciona texto extra)
-	startIdx := strings.Index(response, "{")
-	endIdx := strings.LastIndex(response, "}")
-	
-	if startIdx == -1 || endIdx == -1 {
-		return nil, fmt.Errorf("JSON não encontrado na resposta")
+	ids := make(map[int]bool, len(q.questoes))
+	for _, questao := range q.questoes {
+		ids[questao.ID] = true
 	}
-	
-	jsonStr := response[startIdx : endIdx+1]
 
-	var questaoGerada QuestaoGerada
-	if err := json.Unmarshal([]byte(jsonStr), &questaoGerada); err != nil {
-		return nil, fmt.Errorf("erro ao decodificar questão gerada: %v", err)
+	novas := 0
+	for _, questao := range q.acervo.Questoes() {
+		if !ids[questao.ID] {
+			q.questoes = append(q.questoes, questao)
+			novas++
+		}
+	}
+	if novas > 0 {
+		fmt.Printf("%s %d questões geradas pela IA em sessões anteriores disponíveis offline.\n", ui.Green("📦"), novas)
 	}
+}
 
-	// Validar a questão gerada
-	if err := q.validarQuestao(&questaoGerada); err != nil {
-		return nil, fmt.Errorf("questão inválida: %v", err)
+// guardarGeradas guarda no acervo as questões recém-geradas pela IA, junto
+// com o registro da autoverificação.
+func (q *Quiz) guardarGeradas(questoes ...Questao) error {
+	var erros []error
+	if q.autoverificacao != nil {
+		erros = append(erros, q.autoverificacao.Salvar())
+	}
+	if q.acervo != nil {
+		for _, questao := range questoes {
+			q.acervo.Adicionar(questao, q.gerador.Nome())
+		}
+		erros = append(erros, q.acervo.Salvar())
 	}
+	return errors.Join(erros...)
+}
 
-	questao := &Questao{
-		ID:          rand.Intn(10000) + 1000, // ID aleatório
-		Questao:     questaoGerada.Questao,
-		Opcoes:      questaoGerada.Opcoes,
-		Resposta:    questaoGerada.Resposta,
-		Explicacao:  questaoGerada.Explicacao,
-		Dificuldade: questaoGerada.Dificuldade,
-		Categoria:   questaoGerada.Categoria,
+// MostrarAutoverificacao mostra, para cada par de modelos, em quantas
+// questões o modelo que respondeu concordou com o que gerou.
+func (q *Quiz) MostrarAutoverificacao() {
+	if q.autoverificacao == nil {
+		return
+	}
+	pares := q.autoverificacao.Concordancias()
+	if len(pares) == 0 {
+		return
 	}
 
-	return questao, nil
+	fmt.Println()
+	fmt.Println(ui.Cyan("🔎 Autoverificação (respostas às cegas que batem com a questão gerada):"))
+	for _, p := range pares {
+		fmt.Printf("   %s → %s: %s (%d de %d)\n", p.Gerador, p.Respondedor,
+			ui.Bold(fmt.Sprintf("%.0f%%", p.Taxa()*100)), p.Concordou, p.Concordou+p.Discordou)
+	}
 }
 
-func (q *Quiz) validarQuestao(questao *QuestaoGerada) error {
-	if questao.Questao == "" {
-		return fmt.Errorf("questão vazia")
-	}
-	
-	if len(questao.Opcoes) != 4 {
-		return fmt.Errorf("deve ter exatamente 4 opções, encontradas: %d", len(questao.Opcoes))
-	}
-	
-	// Verificar se a resposta está entre as opções
-	respostaEncontrada := false
-	for _, opcao := range questao.Opcoes {
-		if strings.TrimSpace(opcao) == strings.TrimSpace(questao.Resposta) {
-			respostaEncontrada = true
-			break
+// ResolverCaminhosBanco retorna os caminhos informados ou, se não houver
+// nenhum, o diretório BancoPadrao quando ele existe.
+func ResolverCaminhosBanco(caminhos []string) []string {
+	if len(caminhos) == 0 {
+		if info, err := os.Stat(BancoPadrao); err == nil && info.IsDir() {
+			return []string{BancoPadrao}
 		}
 	}
-	
-	if !respostaEncontrada {
-		return fmt.Errorf("resposta '%s' não encontrada nas opções", questao.Resposta)
+	return caminhos
+}
+
+func (q *Quiz) carregarQuestoes(caminhos []string) {
+	caminhos = ResolverCaminhosBanco(caminhos)
+
+	questoes, err := CarregarBancoCompleto(caminhos...)
+	if err != nil {
+		fmt.Println(ui.Yellow("⚠️  Problemas ao carregar o banco de questões:"))
+		fmt.Println(err)
+	}
+
+	q.questoes = questoes
+	if len(caminhos) > 0 {
+		fmt.Printf("%s %d questões disponíveis no banco.\n", ui.Green("📚"), len(q.questoes))
 	}
-	
-	return nil
 }
 
-func (q *Quiz) gerarQuestoes(quantidade int, dificuldade string) []Questao {
-	if !q.usarOllama {
-		return q.questoes
+// sortearQuestoes escolhe até quantidade questões do banco, opcionalmente
+// filtrando pela dificuldade, pela categoria e pelo tipo.
+func (q *Quiz) sortearQuestoes(quantidade int, dificuldade, categoria, tipo string) []Questao {
+	var candidatas []Questao
+	for _, questao := range q.questoes {
+		if dificuldade != "" && questao.Dificuldade != dificuldade {
+			continue
+		}
+		if categoria != "" && !strings.EqualFold(questao.Categoria, categoria) {
+			continue
+		}
+		if tipo != "" && questao.TipoQuestao() != tipo {
+			continue
+		}
+		candidatas = append(candidatas, questao)
 	}
 
-	categorias := []string{"sintaxe", "tipos", "concorrencia", "bibliotecas", "interfaces", "erros", "estruturas", "Goroutines", "testes", "garbage collector", "banco de dados", "segurança e boas práticas"}
-	questoes := make([]Questao, 0, quantidade)
+	rand.Shuffle(len(candidatas), func(i, j int) {
+		candidatas[i], candidatas[j] = candidatas[j], candidatas[i]
+	})
+
+	if len(candidatas) > quantidade {
+		return candidatas[:quantidade]
+	}
+	return candidatas
+}
 
-	fmt.Printf("%s Gerando %d questões com IA...\n", ui.Magenta("🤖"), quantidade)
-	
-	// Barra de progresso
-	spinner, _ := pterm.DefaultSpinner.Start(ui.Cyan("Conectando com a IA..."))
+func (q *Quiz) opcoesGeracao() OpcoesGeracao {
+	return OpcoesGeracao{
+		Trabalhadores: q.config.TrabalhadoresGeracao,
+		Intervalo:     q.config.IntervaloGeracao,
+		Timeout:       q.config.TimeoutGeracao,
+	}
+}
 
-	for i := 0; i < quantidade; i++ {
-		categoria := categorias[rand.Intn(len(categorias))]
-		dif := dificuldade
-		
-		// Se não especificou dificuldade, escolher aleatoriamente
-		if dif == "" {
-			dificuldades := []string{"facil", "medio", "dificil"}
-			dif = dificuldades[rand.Intn(len(dificuldades))]
-		}
+// IAAtiva informa se o gerador de IA respondeu na inicialização.
+func (q *Quiz) IAAtiva() bool {
+	return q.usarIA
+}
 
-		spinner.UpdateText(fmt.Sprintf("Gerando questão %d/%d - %s (%s)", i+1, quantidade, categoria, dif))
+// GerarQuestoesIA gera questões apenas com a IA, sem completar com o banco.
+// Falhas individuais são relatadas no erro retornado junto com as questões
+// geradas. Cancelar ctx interrompe a geração.
+func (q *Quiz) GerarQuestoesIA(ctx context.Context, quantidade int, dificuldade, categoria, tipo string) ([]Questao, error) {
+	if !q.usarIA {
+		return nil, fmt.Errorf("%s não está disponível", q.gerador.Nome())
+	}
 
-		questao, err := q.gerarQuestaoComOllama(dif, categoria)
-		if err != nil {
-			fmt.Printf("\n%s Erro ao gerar questão %d: %v\n", ui.Red("❌"), i+1, err)
-			fmt.Printf("%s Usando questão pré-definida como fallback.\n", ui.Yellow("⚠️"))
-			
-			// Usar questão de fallback
-			if i < len(q.questoes) {
-				questoes = append(questoes, q.questoes[i])
-			}
+	var questoes []Questao
+	var erros []error
+	pedidos := montarPedidos(quantidade, dificuldade, categoria, tipo)
+	for res := range GerarEmParalelo(ctx, q.gerador, pedidos, q.opcoesGeracao()) {
+		if res.Err != nil {
+			erros = append(erros, fmt.Errorf("questão %d: %v", res.Indice+1, res.Err))
 			continue
 		}
-
-		questoes = append(questoes, *questao)
-		time.Sleep(1 * time.Second) // Evitar sobrecarregar a API
+		questoes = append(questoes, *res.Questao)
 	}
 
-	if len(questoes) > 0 {
-		spinner.Success(fmt.Sprintf("✅ %d questões geradas pela IA!", len(questoes)))
-	} else {
-		spinner.Fail("❌ Falha ao gerar questões. Usando questões pré-definidas.")
-		return q.questoes
+	if err := q.guardarGeradas(questoes...); err != nil {
+		erros = append(erros, err)
 	}
-
-	return questoes
+	return questoes, errors.Join(erros...)
 }
 
 func (q *Quiz) MostrarEstatisticas() {
@@ -298,6 +357,10 @@ func (q *Quiz) MostrarEstatisticas() {
 	fmt.Println(ui.Cyan("╚══════════════════════════════════════════════════════════╝"))
 	fmt.Println()
 
+	if q.config.Perfil != "" {
+		fmt.Printf("%s Perfil: %s\n", ui.Cyan("👤"), ui.Bold(q.config.Perfil))
+	}
+
 	fmt.Printf("%s Total de quizzes realizados: %s\n",
 		ui.Magenta("🎯"),
 		ui.Bold(fmt.Sprintf("%d", q.stats.TotalQuizzes)))
@@ -315,287 +378,167 @@ func (q *Quiz) MostrarEstatisticas() {
 		ui.Blue("📈"),
 		ui.Bold(fmt.Sprintf("%.1f%%", q.stats.MediaPercentual)))
 
+	fmt.Printf("%s Nível estimado: %s (questões de nível %s)\n",
+		ui.Cyan("🧭"),
+		ui.Bold(fmt.Sprintf("%.0f", q.stats.HabilidadeAtual())),
+		ui.Bold(stats.NivelHabilidade(q.stats.HabilidadeAtual())))
+
 	if q.stats.UltimoQuiz != "" {
 		fmt.Printf("%s Último quiz: %s\n",
 			ui.Cyan("📅"),
 			ui.Bold(q.stats.UltimoQuiz))
 	}
 
-	if q.usarOllama {
-		fmt.Printf("%s Modo IA: %s (Modelo: %s)\n",
+	q.mostrarHistorico()
+
+	if q.usarIA {
+		fmt.Printf("%s Modo IA: %s (%s)\n",
 			ui.Green("🤖"),
 			ui.Bold("ATIVO"),
-			ui.Bold(q.ollamaModel))
+			ui.Bold(q.gerador.Nome()))
 	} else {
 		fmt.Printf("%s Modo IA: %s\n",
 			ui.Red("🤖"),
 			ui.Bold("DESATIVADO"))
 	}
+	q.MostrarAutoverificacao()
 
 	fmt.Println()
 }
 
-func (q *Quiz) SelecionarModoJogo() string {
-	options := []string{
-		"🎯 Todas as questões (10 questões)",
-		"⚡ Quiz rápido (5 questões aleatórias)",
-		"🧠 Apenas questões difíceis",
-		"📊 Ver estatísticas",
+// mostrarHistorico mostra o desempenho por categoria, por dificuldade e por
+// semana, calculado a partir das respostas registradas.
+func (q *Quiz) mostrarHistorico() {
+	if len(q.stats.Respostas) == 0 {
+		return
 	}
 
-	// Adicionar opções específicas para IA se disponível
-	if q.usarOllama {
-		options = append([]string{
-			"🤖 IA: Quiz personalizado (5 questões geradas)",
-			"🎓 IA: Questões avançadas (3 questões difíceis)",
-			"🚀 IA: Desafio extremo (10 questões mistas)",
-		}, options...)
+	fmt.Println()
+	fmt.Println(ui.Cyan("📂 Desempenho por categoria:"))
+	for _, d := range q.stats.PorCategoria() {
+		fmt.Printf("   %-26s %s\n", d.Nome, linhaDesempenho(d))
 	}
 
-	options = append(options, "❌ Sair")
-
-	var modo string
-	prompt := &survey.Select{
-		Message: "Escolha o modo de jogo:",
-		Options: options,
+	fmt.Println()
+	fmt.Println(ui.Cyan("🎚️  Desempenho por dificuldade:"))
+	porDificuldade := q.stats.PorDificuldade()
+	sort.SliceStable(porDificuldade, func(i, j int) bool {
+		return slices.Index(Dificuldades, porDificuldade[i].Nome) < slices.Index(Dificuldades, porDificuldade[j].Nome)
+	})
+	for _, d := range porDificuldade {
+		fmt.Printf("   %-26s %s\n", d.Nome, linhaDesempenho(d))
 	}
 
-	survey.AskOne(prompt, &modo)
-	return modo
+	fmt.Println()
+	fmt.Println(ui.Cyan("📆 Tendência semanal:"))
+	for _, p := range q.stats.Tendencia(8) {
+		fmt.Printf("   %-26s %s\n", "semana de "+p.Inicio.Format("02/01/2006"), linhaDesempenho(p.Desempenho))
+	}
+	fmt.Println()
 }
 
-func (q *Quiz) FiltrarQuestoes(modo string) []Questao {
+func linhaDesempenho(d stats.Desempenho) string {
+	percentual := d.Percentual()
+	cor := ui.Red
 	switch {
-	case strings.Contains(modo, "IA: Quiz personalizado"):
-		return q.gerarQuestoes(5, "")
-	case strings.Contains(modo, "IA: Questões avançadas"):
-		return q.gerarQuestoes(3, "dificil")
-	case strings.Contains(modo, "IA: Desafio extremo"):
-		return q.gerarQuestoes(10, "")
-	case strings.Contains(modo, "Todas as questões"):
-		if q.usarOllama {
-			return q.gerarQuestoes(10, "")
-		}
-		return q.questoes
-	case strings.Contains(modo, "Quiz rápido"):
-		if q.usarOllama {
-			return q.gerarQuestoes(5, "")
-		}
-		// Fallback para questões pré-definidas
-		questoesAleatorias := make([]Questao, len(q.questoes))
-		copy(questoesAleatorias, q.questoes)
-
-		rand.Seed(time.Now().UnixNano())
-		rand.Shuffle(len(questoesAleatorias), func(i, j int) {
-			questoesAleatorias[i], questoesAleatorias[j] = questoesAleatorias[j], questoesAleatorias[i]
-		})
-
-		if len(questoesAleatorias) > 5 {
-			return questoesAleatorias[:5]
-		}
-		return questoesAleatorias
-	case strings.Contains(modo, "difíceis"):
-		if q.usarOllama {
-			return q.gerarQuestoes(5, "dificil")
-		}
-		// Fallback para questões pré-definidas
-		var dificeis []Questao
-		for _, q := range q.questoes {
-			if q.Dificuldade == "dificil" {
-				dificeis = append(dificeis, q)
-			}
-		}
-		return dificeis
-	default:
-		return q.questoes
+	case percentual >= 80:
+		cor = ui.Green
+	case percentual >= 60:
+		cor = ui.Yellow
 	}
-}
-
-// O resto dos métodos permanecem iguais...
-func (q *Quiz) ExecutarQuiz(questoesSelecionadas []Questao) {
-	fmt.Println()
-	fmt.Printf("%s Você terá %s questões para responder!\n",
-		ui.Magenta("📚"),
-		ui.Bold(fmt.Sprintf("%d", len(questoesSelecionadas))))
-	fmt.Println()
-
-	score := 0
-	respostasCorretas := []bool{}
-	tempoInicio := time.Now()
-
-	for i, questao := range questoesSelecionadas {
-		ui.LimparTela()
-		fmt.Println(ui.Cyan("═══════════════════════════════════════════════════════════"))
-		fmt.Printf("%s Questão %d de %d | %s | %s\n",
-			ui.Yellow("📝"),
-			i+1,
-			len(questoesSelecionadas),
-			ui.Blue(fmt.Sprintf("Categoria: %s", questao.Categoria)),
-			q.getDificuldadeIcon(questao.Dificuldade))
-		fmt.Println(ui.Cyan("═══════════════════════════════════════════════════════════"))
-		fmt.Println()
-
-		var resposta string
-		prompt := &survey.Select{
-			Message: ui.Bold(questao.Questao),
-			Options: questao.Opcoes,
-		}
-
-		err := survey.AskOne(prompt, &resposta)
-		if err != nil {
-			fmt.Printf(ui.Red("Erro ao ler resposta: %v\n"), err)
-			continue
-		}
 
-		fmt.Println()
-
-		if strings.TrimSpace(resposta) == questao.Resposta {
-			fmt.Println(ui.Green("✅ Resposta correta! Parabéns!"))
-			score++
-			respostasCorretas = append(respostasCorretas, true)
-		} else {
-			fmt.Printf(ui.Red("❌ Resposta incorreta! A resposta correta é: %s\n"),
-				ui.Bold(questao.Resposta))
-			respostasCorretas = append(respostasCorretas, false)
-		}
-
-		fmt.Printf("%s %s\n", ui.Blue("💡 Explicação:"), questao.Explicacao)
-		fmt.Println()
-
-		if i < len(questoesSelecionadas)-1 {
-			fmt.Printf(ui.Magenta("📊 Progresso: %d/%d questões | Acertos: %d\n"),
-				i+1, len(questoesSelecionadas), score)
-			fmt.Println()
-
-			var continuar bool
-			continuePrompt := &survey.Confirm{
-				Message: "Continuar para a próxima questão?",
-				Default: true,
-			}
-			survey.AskOne(continuePrompt, &continuar)
-
-			if !continuar {
-				fmt.Println(ui.Yellow("Quiz interrompido pelo usuário."))
-				return
-			}
-			fmt.Println()
-		}
+	cheios := int(percentual / 10)
+	barra := strings.Repeat("█", cheios) + strings.Repeat("░", 10-cheios)
+	linha := fmt.Sprintf("%s %4d/%-4d %s  ⏱️ %5.1fs",
+		cor(barra), d.Acertos, d.Respondidas,
+		ui.Bold(fmt.Sprintf("%5.1f%%", percentual)),
+		d.TempoMedio().Seconds())
+	if d.SemTempo > 0 {
+		linha += ui.Yellow(fmt.Sprintf("  ⏰ %d sem resposta", d.SemTempo))
 	}
-
-	tempoTotal := time.Since(tempoInicio)
-	q.MostrarResultados(score, len(questoesSelecionadas), respostasCorretas, tempoTotal)
-	q.AtualizarEstatisticas(score, len(questoesSelecionadas))
+	return linha
 }
 
-func (q *Quiz) getDificuldadeIcon(dificuldade string) string {
-	switch dificuldade {
-	case "facil":
-		return ui.Green("🟢 Fácil")
-	case "medio":
-		return ui.Yellow("🟡 Médio")
-	case "dificil":
-		return ui.Red("🔴 Difícil")
-	default:
-		return ui.Blue("🔵 Normal")
+// OpcoesMenu retorna as opções do menu de modos de jogo, com as quantidades
+// da configuração. Veja SelecaoDoMenu.
+func (q *Quiz) OpcoesMenu() []string {
+	qtd := q.config.Questoes
+	options := []string{
+		fmt.Sprintf("🎯 Todas as questões (%d questões)", qtd.Todas),
+		fmt.Sprintf("⚡ Quiz rápido (%d questões aleatórias)", qtd.Rapido),
+		"🧠 Apenas questões difíceis",
+		fmt.Sprintf("🔁 Revisão (%d questões para hoje)", q.RevisoesPendentes()),
+		fmt.Sprintf("📈 Adaptativo (%d questões no seu nível)", qtd.Adaptativo),
+		fmt.Sprintf("🩹 Foco nos pontos fracos (%d questões)", qtd.PontosFracos),
+		fmt.Sprintf("⏱️  Prova (%d questões em %s)", qtd.Prova, q.config.TempoProva),
+		"📊 Ver estatísticas",
+		"🏅 Recordes",
+	}
+	if q.config.Perfil != "" {
+		options = append(options, fmt.Sprintf("👤 Trocar de perfil (%s)", q.config.Perfil))
 	}
-}
-
-func (q *Quiz) MostrarResultados(score, total int, respostasCorretas []bool, tempo time.Duration) {
-	fmt.Println()
-	fmt.Println(ui.Cyan("╔══════════════════════════════════════════════════════════╗"))
-	fmt.Println(ui.Cyan("║") + "                    " + ui.Bold("🏆 RESULTADOS FINAIS 🏆") + "                    " + ui.Cyan("║"))
-	fmt.Println(ui.Cyan("╚══════════════════════════════════════════════════════════╝"))
-	fmt.Println()
-
-	spinner, _ := pterm.DefaultSpinner.Start(ui.Magenta("Calculando resultados..."))
-	time.Sleep(2 * time.Second)
-	spinner.Success(pterm.Green("Cálculos finalizados!"))
-	fmt.Println()
-
-	percentual := float64(score) / float64(total) * 100
-
-	fmt.Printf("%s Você acertou %s de %s questões\n",
-		ui.Magenta("📊"),
-		ui.Bold(ui.Green(fmt.Sprintf("%d", score))),
-		ui.Bold(fmt.Sprintf("%d", total)))
 
-	fmt.Printf("%s Percentual de acertos: %s\n",
-		ui.Magenta("📈"),
-		ui.Bold(fmt.Sprintf("%.1f%%", percentual)))
+	// Adicionar opções específicas para IA se disponível
+	if q.usarIA {
+		options = append([]string{
+			fmt.Sprintf("🤖 IA: Quiz personalizado (%d questões geradas)", qtd.IAPersonalizado),
+			fmt.Sprintf("🎓 IA: Questões avançadas (%d questões difíceis)", qtd.IAAvancado),
+			fmt.Sprintf("🚀 IA: Desafio extremo (%d questões mistas)", qtd.IAExtremo),
+		}, options...)
+	}
 
-	fmt.Printf("%s Tempo total: %s\n",
-		ui.Blue("⏱️"),
-		ui.Bold(fmt.Sprintf("%.1f segundos", tempo.Seconds())))
+	return append(options, "❌ Sair")
+}
 
-	fmt.Printf("%s Tempo médio por questão: %s\n",
-		ui.Blue("⚡"),
-		ui.Bold(fmt.Sprintf("%.1f segundos", tempo.Seconds()/float64(total))))
+// AtualizarEstatisticas soma um quiz terminado aos totais e salva as
+// estatísticas. Um quiz sem nenhuma questão respondida não conta.
+func (q *Quiz) AtualizarEstatisticas(score, total int, respostas []stats.Resposta) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
 
-	fmt.Println()
+	q.stats.Registrar(respostas...)
+	if total > 0 {
+		q.stats.TotalQuizzes++
+		q.stats.TotalAcertos += score
+		q.stats.TotalQuestoes += total
 
-	fmt.Println(ui.Cyan("📋 Resumo das suas respostas:"))
-	for i, correto := range respostasCorretas {
-		status := ui.Red("❌")
-		if correto {
-			status = ui.Green("✅")
+		if score > q.stats.MelhorScore {
+			q.stats.MelhorScore = score
 		}
-		fmt.Printf("   Questão %d: %s\n", i+1, status)
+		q.stats.UltimoQuiz = time.Now().Format(stats.FormatoUltimoQuiz)
+	}
+	if q.stats.TotalQuestoes > 0 {
+		q.stats.MediaPercentual = float64(q.stats.TotalAcertos) / float64(q.stats.TotalQuestoes) * 100
 	}
-	fmt.Println()
 
-	q.MostrarMensagemFinal(score, total, percentual)
+	return stats.SalvarEstatisticas(q.statsFile, q.stats)
 }
 
-func (q *Quiz) MostrarMensagemFinal(score, total int, percentual float64) {
-	switch {
-	case score == total:
-		fmt.Println(ui.Green("🎉 PERFEITO! Você acertou todas as questões!"))
-		fmt.Println(ui.Green("🏆 Você é um verdadeiro expert em Go!"))
-		fmt.Println(ui.Green("🌟 Considerado um GoGuru!"))
-	case percentual >= 80:
-		fmt.Println(ui.Green("🌟 Excelente! Você tem um ótimo conhecimento em Go!"))
-		fmt.Println(ui.Green("👏 Continue assim!"))
-		fmt.Println(ui.Blue("🚀 Próximo nível: tente as questões difíceis!"))
-	case percentual >= 60:
-		fmt.Println(ui.Yellow("👍 Muito bem! Você está no caminho certo!"))
-		fmt.Println(ui.Yellow("📚 Continue estudando para melhorar ainda mais!"))
-		fmt.Println(ui.Blue("💡 Dica: revise os conceitos que errou!"))
-	case percentual >= 40:
-		fmt.Println(ui.Yellow("😊 Bom começo! Você já sabe algumas coisas sobre Go!"))
-		fmt.Println(ui.Yellow("💪 Com mais estudo você chegará lá!"))
-		fmt.Println(ui.Blue("📖 Recomendo focar nos fundamentos primeiro!"))
-	default:
-		fmt.Println(ui.Red("📖 Você precisa estudar mais sobre Go!"))
-		fmt.Println(ui.Red("💡 Que tal revisar a documentação oficial?"))
-		fmt.Println(ui.Yellow("🔗 Recursos recomendados:"))
-		fmt.Println(ui.Cyan("   • https://golang.org/doc/"))
-		fmt.Println(ui.Cyan("   • https://tour.golang.org/"))
-		fmt.Println(ui.Cyan("   • https://gobyexample.com/"))
+// registrarRespostas guarda no histórico as respostas de um quiz interrompido.
+func (q *Quiz) registrarRespostas(respostas []stats.Resposta) {
+	if len(respostas) == 0 {
+		return
 	}
-	fmt.Println()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.stats.Registrar(respostas...)
+	_ = stats.SalvarEstatisticas(q.statsFile, q.stats)
 }
 
-func (q *Quiz) AtualizarEstatisticas(score, total int) {
-	q.stats.TotalQuizzes++
-	q.stats.TotalAcertos += score
-	q.stats.TotalQuestoes += total
-
-	if score > q.stats.MelhorScore {
-		q.stats.MelhorScore = score
-	}
-
-	q.stats.MediaPercentual = float64(q.stats.TotalAcertos) / float64(q.stats.TotalQuestoes) * 100
-	q.stats.UltimoQuiz = time.Now().Format("02/01/2006 15:04")
-
-	_ = stats.SalvarEstatisticas(q.statsFile, q.stats)
+// Estatisticas retorna uma cópia das estatísticas do jogador.
+func (q *Quiz) Estatisticas() stats.Estatisticas {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	copia := q.stats
+	copia.Respostas = slices.Clone(q.stats.Respostas)
+	copia.Revisoes = maps.Clone(q.stats.Revisoes)
+	copia.Partidas = slices.Clone(q.stats.Partidas)
+	return copia
 }
 
-func (q *Quiz) JogarNovamente() bool {
-	var jogarNovamente bool
-	playAgainPrompt := &survey.Confirm{
-		Message: "Gostaria de jogar novamente?",
-		Default: false,
-	}
-	survey.AskOne(playAgainPrompt, &jogarNovamente)
-	return jogarNovamente
-}
\ No newline at end of file
+// habilidade retorna a habilidade estimada do jogador.
+func (q *Quiz) habilidade() float64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.stats.HabilidadeAtual()
+}
@@ -0,0 +1,227 @@
+package quiz
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"quiz_go/internal/perfil"
+	"quiz_go/internal/stats"
+)
+
+// Quadro é o quadro de recordes de um modo e uma dificuldade, com as
+// melhores partidas de todos os perfis. Dificuldade vazia reúne os quizzes
+// que misturam dificuldades.
+type Quadro struct {
+	Modo        Modo
+	Dificuldade string
+	Registros   []Registro // da melhor para a pior; veja stats.CompararPartidas
+}
+
+// Registro é uma partida em um quadro, com o perfil que a jogou.
+type Registro struct {
+	Perfil string
+	stats.Partida
+}
+
+// MontarQuadros separa as partidas de cada perfil em quadros de modo e
+// dificuldade, com até limite registros cada (zero não limita). Os quadros
+// seguem a ordem de Modos e de Dificuldades.
+func MontarQuadros(partidas map[string][]stats.Partida, limite int) []Quadro {
+	type chave struct {
+		modo        Modo
+		dificuldade string
+	}
+	registros := make(map[chave][]Registro)
+	for nome, lista := range partidas {
+		for _, p := range lista {
+			c := chave{Modo(p.Modo), p.Dificuldade}
+			registros[c] = append(registros[c], Registro{Perfil: nome, Partida: p})
+		}
+	}
+
+	quadros := make([]Quadro, 0, len(registros))
+	for c, lista := range registros {
+		slices.SortFunc(lista, func(a, b Registro) int {
+			return cmp.Or(stats.CompararPartidas(a.Partida, b.Partida), cmp.Compare(a.Perfil, b.Perfil))
+		})
+		if limite > 0 && len(lista) > limite {
+			lista = lista[:limite]
+		}
+		quadros = append(quadros, Quadro{Modo: c.modo, Dificuldade: c.dificuldade, Registros: lista})
+	}
+	slices.SortFunc(quadros, func(a, b Quadro) int {
+		return cmp.Or(
+			cmp.Compare(ordemModo(a.Modo), ordemModo(b.Modo)),
+			cmp.Compare(string(a.Modo), string(b.Modo)),
+			cmp.Compare(slices.Index(Dificuldades, a.Dificuldade), slices.Index(Dificuldades, b.Dificuldade)),
+		)
+	})
+	return quadros
+}
+
+// ordemModo retorna a posição do modo em Modos; modos desconhecidos, de
+// versões futuras, ficam no fim.
+func ordemModo(m Modo) int {
+	if i := slices.Index(Modos, m); i >= 0 {
+		return i
+	}
+	return len(Modos)
+}
+
+// Nome retorna o nome do quadro, como "Quiz rápido" ou "Prova (dificil)".
+func (q Quadro) Nome() string {
+	if q.Dificuldade == "" {
+		return q.Modo.Nome()
+	}
+	return q.Modo.Nome() + " (" + q.Dificuldade + ")"
+}
+
+// registroExportado é um registro de quadro nos arquivos de SalvarQuadros.
+type registroExportado struct {
+	Modo        Modo      `json:"modo"`
+	Dificuldade string    `json:"dificuldade,omitempty"`
+	Posicao     int       `json:"posicao"`
+	Perfil      string    `json:"perfil"`
+	Acertos     int       `json:"acertos"`
+	Respondidas int       `json:"respondidas"`
+	Percentual  float64   `json:"percentual"`
+	DuracaoSegs float64   `json:"duracao_s"`
+	Sequencia   int       `json:"sequencia"`
+	Momento     time.Time `json:"momento"`
+}
+
+// SalvarQuadros grava os quadros em um arquivo CSV ou JSON, conforme a
+// extensão, com uma linha por registro.
+func SalvarQuadros(arquivo string, quadros []Quadro) error {
+	var registros []registroExportado
+	for _, quadro := range quadros {
+		for i, r := range quadro.Registros {
+			registros = append(registros, registroExportado{
+				Modo:        quadro.Modo,
+				Dificuldade: quadro.Dificuldade,
+				Posicao:     i + 1,
+				Perfil:      r.Perfil,
+				Acertos:     r.Acertos,
+				Respondidas: r.Respondidas,
+				Percentual:  r.Percentual(),
+				DuracaoSegs: r.Duracao().Seconds(),
+				Sequencia:   r.Sequencia,
+				Momento:     r.Momento,
+			})
+		}
+	}
+
+	var data []byte
+	switch strings.ToLower(filepath.Ext(arquivo)) {
+	case ".json":
+		if registros == nil {
+			registros = []registroExportado{}
+		}
+		var err error
+		if data, err = json.MarshalIndent(registros, "", "  "); err != nil {
+			return fmt.Errorf("erro ao serializar recordes: %v", err)
+		}
+	case ".csv":
+		data = recordesCSV(registros)
+	default:
+		return fmt.Errorf("formato não suportado: %s (use .csv ou .json)", arquivo)
+	}
+
+	if dir := filepath.Dir(arquivo); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(arquivo, data, 0644)
+}
+
+func recordesCSV(registros []registroExportado) []byte {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Write([]string{"modo", "dificuldade", "posicao", "perfil", "acertos", "respondidas", "percentual", "duracao_s", "sequencia", "momento"})
+	for _, r := range registros {
+		w.Write([]string{
+			string(r.Modo),
+			r.Dificuldade,
+			strconv.Itoa(r.Posicao),
+			r.Perfil,
+			strconv.Itoa(r.Acertos),
+			strconv.Itoa(r.Respondidas),
+			strconv.FormatFloat(r.Percentual, 'f', 1, 64),
+			strconv.FormatFloat(r.DuracaoSegs, 'f', 1, 64),
+			strconv.Itoa(r.Sequencia),
+			r.Momento.Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+	return []byte(b.String())
+}
+
+// Quadros monta os quadros de recordes com as partidas de todos os perfis.
+// Sem perfil, só as do jogador atual entram.
+func (q *Quiz) Quadros(limite int) ([]Quadro, error) {
+	partidas, err := q.partidasOutrosPerfis()
+	if err != nil {
+		return nil, err
+	}
+	q.mu.Lock()
+	partidas[q.config.Perfil] = slices.Clone(q.stats.Partidas)
+	q.mu.Unlock()
+	return MontarQuadros(partidas, limite), nil
+}
+
+// partidasOutrosPerfis lê as partidas guardadas dos perfis, menos o atual,
+// cujas estatísticas em memória podem estar mais novas que as do arquivo.
+func (q *Quiz) partidasOutrosPerfis() (map[string][]stats.Partida, error) {
+	if q.config.Perfil == "" {
+		return make(map[string][]stats.Partida), nil
+	}
+	partidas, err := perfil.Perfis{Dir: q.config.DiretorioDados}.Partidas()
+	if err != nil {
+		return nil, err
+	}
+	delete(partidas, q.config.Perfil)
+	return partidas, nil
+}
+
+// registrarPartida guarda a partida nas estatísticas, sem salvá-las, e
+// informa se ela ficou à frente de todas as do quadro, de todos os perfis, e
+// de todas as do jogador.
+func (q *Quiz) registrarPartida(p stats.Partida) (recorde, pessoal bool) {
+	outros, _ := q.partidasOutrosPerfis()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	todas := slices.Clone(q.stats.Partidas)
+	for _, lista := range outros {
+		todas = append(todas, lista...)
+	}
+	recorde = superaTodas(p, todas)
+	pessoal = superaTodas(p, q.stats.Partidas)
+	q.stats.Partidas = append(q.stats.Partidas, p)
+	return recorde, pessoal
+}
+
+// superaTodas informa se p fica à frente de todas as partidas do mesmo
+// quadro. Sem nenhuma delas, não há recorde a bater.
+func superaTodas(p stats.Partida, partidas []stats.Partida) bool {
+	houve := false
+	for _, outra := range partidas {
+		if outra.Modo != p.Modo || outra.Dificuldade != p.Dificuldade {
+			continue
+		}
+		if stats.CompararPartidas(p, outra) >= 0 {
+			return false
+		}
+		houve = true
+	}
+	return houve
+}
@@ -0,0 +1,158 @@
+package quiz
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"quiz_go/internal/perfil"
+	"quiz_go/internal/stats"
+)
+
+func TestMontarQuadros(t *testing.T) {
+	inicio := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	partida := func(modo Modo, dificuldade string, acertos, respondidas int, segundos int64, sequencia, minuto int) stats.Partida {
+		return stats.Partida{
+			Sessao:      fmt.Sprint(minuto),
+			Modo:        string(modo),
+			Dificuldade: dificuldade,
+			Acertos:     acertos,
+			Respondidas: respondidas,
+			DuracaoMs:   segundos * 1000,
+			Sequencia:   sequencia,
+			Momento:     inicio.Add(time.Duration(minuto) * time.Minute),
+		}
+	}
+	partidas := map[string][]stats.Partida{
+		"Ana": {
+			partida(ModoProva, "", 8, 10, 100, 5, 1),
+			partida(ModoRapido, "", 3, 3, 30, 3, 2),
+			partida(ModoRapido, "", 3, 10, 30, 2, 3),
+			partida(ModoRapido, "facil", 3, 3, 30, 3, 4),
+		},
+		"Bia": {
+			partida(ModoRapido, "", 3, 3, 20, 3, 5),  // mesmo percentual, mais rápida
+			partida(ModoRapido, "", 9, 10, 20, 4, 6), // mesmo tempo, menor percentual
+			partida(ModoRapido, "", 9, 10, 20, 6, 7), // maior sequência
+		},
+		"Caio": {
+			partida(ModoRapido, "dificil", 5, 5, 40, 5, 8),    // 8s por questão
+			partida(ModoRapido, "dificil", 10, 10, 60, 10, 9), // mais longa, mas 6s por questão
+		},
+	}
+
+	quadros := MontarQuadros(partidas, 4)
+	var nomes []string
+	for _, q := range quadros {
+		nomes = append(nomes, q.Nome())
+	}
+	if got := strings.Join(nomes, " | "); got != "Quiz rápido | Quiz rápido (facil) | Quiz rápido (dificil) | Prova" {
+		t.Fatalf("quadros = %s", got)
+	}
+
+	var ranking []string
+	for _, r := range quadros[0].Registros {
+		ranking = append(ranking, fmt.Sprintf("%s:%s", r.Perfil, r.Sessao))
+	}
+	if got := strings.Join(ranking, " "); got != "Bia:5 Ana:2 Bia:7 Bia:6" {
+		t.Errorf("ranking = %s; a partida de 3/10 deveria ficar de fora pelo limite", got)
+	}
+
+	if r := quadros[2].Registros; len(r) != 2 || r[0].Sessao != "9" {
+		t.Errorf("quadro %s = %+v; a partida de 10 questões tem o menor tempo médio", quadros[2].Nome(), r)
+	}
+}
+
+// perfilComPartida cria o perfil nome com uma partida do modo rápido.
+func perfilComPartida(t *testing.T, p perfil.Perfis, nome string, partida stats.Partida) {
+	t.Helper()
+	if err := p.Criar(nome); err != nil {
+		t.Fatal(err)
+	}
+	if err := stats.SalvarEstatisticas(p.ArquivoStats(nome), stats.Estatisticas{Partidas: []stats.Partida{partida}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResumoInformaORecorde(t *testing.T) {
+	q := novoQuizOffline(t)
+	perfis := perfil.Perfis{Dir: t.TempDir()}
+	q.config.Perfil, q.config.DiretorioDados = "Ana", perfis.Dir
+	perfilComPartida(t, perfis, "Bia", stats.Partida{
+		Sessao: "b", Modo: string(ModoRapido), Acertos: 1, Respondidas: 2, DuracaoMs: 60000, Sequencia: 1, Momento: time.Now(),
+	})
+
+	jogar := func(respostas ...string) *Resumo {
+		r := &roteiro{continuar: -1}
+		for _, escolhida := range respostas {
+			r.respostas = append(r.respostas, respostaRoteiro{escolhida: escolhida})
+		}
+		canal, total := canalCompleto(questoesTeste(len(respostas)))
+		Jogar(q.novaSessao(preparo{modo: ModoRapido, questoes: canal, total: total}), r, r)
+		return r.resumo
+	}
+
+	// Mesmo percentual da Bia, mas bem mais rápido: recorde do quadro, mas
+	// ainda não há outra partida da Ana para bater.
+	if r := jogar("a", "b"); !r.Recorde || r.RecordePessoal {
+		t.Errorf("primeira partida: recorde %v, pessoal %v", r.Recorde, r.RecordePessoal)
+	}
+	if r := jogar("b", "b"); r.Recorde || r.RecordePessoal {
+		t.Errorf("partida pior: recorde %v, pessoal %v", r.Recorde, r.RecordePessoal)
+	}
+	if r := jogar("a", "a"); !r.Recorde || !r.RecordePessoal {
+		t.Errorf("melhor partida: recorde %v, pessoal %v", r.Recorde, r.RecordePessoal)
+	}
+
+	salvas, err := stats.CarregarEstatisticas(q.statsFile)
+	if err != nil || len(salvas.Partidas) != 3 {
+		t.Fatalf("partidas salvas = %d, %v", len(salvas.Partidas), err)
+	}
+	if p := salvas.Partidas[2]; p.Modo != string(ModoRapido) || p.Acertos != 2 || p.Sequencia != 2 {
+		t.Errorf("última partida = %+v", p)
+	}
+
+	quadros, err := q.Quadros(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quadros) != 1 || len(quadros[0].Registros) != 4 || quadros[0].Registros[0].Perfil != "Ana" {
+		t.Errorf("quadros = %+v", quadros)
+	}
+}
+
+func TestSalvarQuadros(t *testing.T) {
+	quadros := []Quadro{{Modo: ModoRapido, Registros: []Registro{
+		{Perfil: "Ana", Partida: stats.Partida{Modo: string(ModoRapido), Acertos: 2, Respondidas: 3, DuracaoMs: 1500, Sequencia: 2}},
+		{Perfil: "Bia", Partida: stats.Partida{Modo: string(ModoRapido), Acertos: 1, Respondidas: 3}},
+	}}}
+	dir := t.TempDir()
+
+	arquivo := filepath.Join(dir, "recordes.csv")
+	if err := SalvarQuadros(arquivo, quadros); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(arquivo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	linhas, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(linhas) != 3 || strings.Join(linhas[1][:8], ",") != "quick,,1,Ana,2,3,66.7,1.5" {
+		t.Errorf("csv = %v", linhas)
+	}
+
+	if err := SalvarQuadros(filepath.Join(dir, "recordes.json"), quadros); err != nil {
+		t.Error(err)
+	}
+	if err := SalvarQuadros(filepath.Join(dir, "recordes.txt"), quadros); err == nil {
+		t.Error("a extensão .txt deveria ser recusada")
+	}
+}
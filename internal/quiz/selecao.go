@@ -0,0 +1,330 @@
+package quiz
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"quiz_go/internal/stats"
+)
+
+// Modo identifica um modo de jogo, independente do texto exibido no menu.
+type Modo string
+
+const (
+	ModoTodas           Modo = "all"
+	ModoRapido          Modo = "quick"
+	ModoDificeis        Modo = "hard"
+	ModoIAPersonalizado Modo = "ai-custom"
+	ModoIAAvancado      Modo = "ai-advanced"
+	ModoIAExtremo       Modo = "ai-extreme"
+	ModoRevisao         Modo = "review"
+	ModoAdaptativo      Modo = "adaptive"
+	ModoPontosFracos    Modo = "weak"
+	ModoProva           Modo = "exam"
+)
+
+// dificuldadeModo guarda a dificuldade usada quando a seleção não a informa.
+// A quantidade padrão de cada modo vem da configuração.
+var dificuldadeModo = map[Modo]string{
+	ModoTodas:           "",
+	ModoRapido:          "",
+	ModoDificeis:        "dificil",
+	ModoIAPersonalizado: "",
+	ModoIAAvancado:      "dificil",
+	ModoIAExtremo:       "",
+	ModoRevisao:         "",
+	ModoAdaptativo:      "",
+	ModoPontosFracos:    "",
+	ModoProva:           "",
+}
+
+// nomesModo guarda o nome de cada modo mostrado ao jogador.
+var nomesModo = map[Modo]string{
+	ModoTodas:           "Todas as questões",
+	ModoRapido:          "Quiz rápido",
+	ModoDificeis:        "Apenas questões difíceis",
+	ModoIAPersonalizado: "IA: Quiz personalizado",
+	ModoIAAvancado:      "IA: Questões avançadas",
+	ModoIAExtremo:       "IA: Desafio extremo",
+	ModoRevisao:         "Revisão",
+	ModoAdaptativo:      "Adaptativo",
+	ModoPontosFracos:    "Foco nos pontos fracos",
+	ModoProva:           "Prova",
+}
+
+// Nome retorna o nome do modo mostrado ao jogador, como "Quiz rápido".
+func (m Modo) Nome() string {
+	if nome, ok := nomesModo[m]; ok {
+		return nome
+	}
+	return string(m)
+}
+
+// SoComIA informa se o modo só faz sentido com a IA ativa. Sem ela, esses
+// modos usam o banco como os demais e ficam fora do menu.
+func (m Modo) SoComIA() bool {
+	switch m {
+	case ModoIAPersonalizado, ModoIAAvancado, ModoIAExtremo:
+		return true
+	}
+	return false
+}
+
+// Pessoal informa se o modo escolhe as questões pelo histórico de um
+// jogador, como a revisão, e não serve para um grupo.
+func (m Modo) Pessoal() bool {
+	switch m {
+	case ModoRevisao, ModoAdaptativo, ModoPontosFracos:
+		return true
+	}
+	return false
+}
+
+// Modos lista os modos aceitos na linha de comando, na ordem do menu.
+var Modos = []Modo{ModoTodas, ModoRapido, ModoDificeis, ModoRevisao, ModoAdaptativo, ModoPontosFracos, ModoProva, ModoIAPersonalizado, ModoIAAvancado, ModoIAExtremo}
+
+// Selecao descreve quais questões um quiz deve usar. Campos vazios usam o
+// padrão do modo.
+type Selecao struct {
+	Modo        Modo
+	Dificuldade string
+	Categoria   string
+	Tipo        string // vazio aceita qualquer tipo do banco; a IA gera múltipla escolha
+	Quantidade  int
+}
+
+// Validar confere se o modo, a dificuldade e o tipo são conhecidos.
+func (s Selecao) Validar() error {
+	if _, ok := dificuldadeModo[s.Modo]; !ok {
+		return fmt.Errorf("modo '%s' desconhecido", s.Modo)
+	}
+	if s.Dificuldade != "" && !contem(Dificuldades, s.Dificuldade) {
+		return fmt.Errorf("dificuldade '%s' inválida (use %s)", s.Dificuldade, strings.Join(Dificuldades, ", "))
+	}
+	if s.Tipo != "" && !contem(Tipos, s.Tipo) {
+		return fmt.Errorf("tipo '%s' inválido (use %s)", s.Tipo, strings.Join(Tipos, ", "))
+	}
+	if s.Quantidade < 0 {
+		return fmt.Errorf("quantidade inválida: %d", s.Quantidade)
+	}
+	return nil
+}
+
+// ValidarCategoria aceita uma das Categorias, sem diferenciar maiúsculas, e
+// retorna o nome como está na lista. Vazio aceita todas as categorias.
+func ValidarCategoria(categoria string) (string, error) {
+	if categoria == "" {
+		return "", nil
+	}
+	if c, ok := categoriaConhecida(categoria); ok {
+		return c, nil
+	}
+	return "", fmt.Errorf("categoria '%s' desconhecida (use uma de: %s)", categoria, strings.Join(Categorias, ", "))
+}
+
+// SelecaoDoMenu converte a opção escolhida no menu interativo em uma Selecao.
+func SelecaoDoMenu(opcao string) Selecao {
+	switch {
+	case strings.Contains(opcao, "IA: Quiz personalizado"):
+		return Selecao{Modo: ModoIAPersonalizado}
+	case strings.Contains(opcao, "IA: Questões avançadas"):
+		return Selecao{Modo: ModoIAAvancado}
+	case strings.Contains(opcao, "IA: Desafio extremo"):
+		return Selecao{Modo: ModoIAExtremo}
+	case strings.Contains(opcao, "Prova"):
+		return Selecao{Modo: ModoProva}
+	case strings.Contains(opcao, "pontos fracos"):
+		return Selecao{Modo: ModoPontosFracos}
+	case strings.Contains(opcao, "Adaptativo"):
+		return Selecao{Modo: ModoAdaptativo}
+	case strings.Contains(opcao, "Revisão"):
+		return Selecao{Modo: ModoRevisao}
+	case strings.Contains(opcao, "Quiz rápido"):
+		return Selecao{Modo: ModoRapido}
+	case strings.Contains(opcao, "difíceis"):
+		return Selecao{Modo: ModoDificeis}
+	default:
+		return Selecao{Modo: ModoTodas}
+	}
+}
+
+// SelecionarQuestoes monta a lista completa de questões de uma seleção,
+// esperando a geração terminar. Veja IniciarQuestoes. Como não há respostas
+// para acompanhar, o modo adaptativo usa só o nível atual do jogador.
+func (q *Quiz) SelecionarQuestoes(sel Selecao) []Questao {
+	if sel.Modo == ModoAdaptativo {
+		if sel.Quantidade == 0 {
+			sel.Quantidade = q.config.QuantidadeModo(string(ModoAdaptativo))
+		}
+		if sel.Dificuldade == "" {
+			sel.Dificuldade = stats.NivelHabilidade(q.habilidade())
+		}
+		sel.Modo = ModoTodas
+	}
+
+	canal, total := q.IniciarQuestoes(context.Background(), sel)
+	questoes := make([]Questao, 0, total)
+	for questao := range canal {
+		questoes = append(questoes, questao)
+	}
+	return questoes
+}
+
+// preparo é o que a seleção monta para um quiz.
+type preparo struct {
+	modo        Modo
+	dificuldade string // vazio: todas as dificuldades
+	questoes    <-chan Questao
+	total       int
+	prova       time.Duration         // tempo total, no modo Prova
+	retorno     chan<- stats.Resposta // recebe as respostas, no modo adaptativo
+}
+
+// IniciarQuestoes começa a montar as questões de uma seleção e as entrega
+// no canal, na ordem do quiz, assim que ficam prontas. Com IA, a geração
+// continua em segundo plano e cada falha é substituída por uma questão do
+// banco; sem IA, o canal já vem completo. Retorna também o total esperado.
+// Cancelar ctx interrompe a geração e completa o resto com o banco.
+// O modo adaptativo espera as respostas para escolher a próxima questão;
+// use IniciarSessao para jogá-lo.
+func (q *Quiz) IniciarQuestoes(ctx context.Context, sel Selecao) (<-chan Questao, int) {
+	p := q.preparar(ctx, sel)
+	return p.questoes, p.total
+}
+
+func (q *Quiz) preparar(ctx context.Context, sel Selecao) preparo {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	quantidade := sel.Quantidade
+	if quantidade == 0 {
+		quantidade = q.config.QuantidadeModo(string(sel.Modo))
+	}
+	dificuldade := sel.Dificuldade
+	if dificuldade == "" {
+		dificuldade = dificuldadeModo[sel.Modo]
+	}
+
+	p := preparo{modo: sel.Modo, dificuldade: dificuldade}
+	if sel.Modo == ModoProva {
+		p.prova = q.config.TempoProva
+	}
+	switch {
+	case sel.Modo == ModoAdaptativo:
+		p.questoes, p.total, p.retorno = q.iniciarAdaptativo(ctx, quantidade, sel.Categoria)
+	case sel.Modo == ModoRevisao:
+		p.questoes, p.total = canalCompleto(q.questoesRevisao(quantidade, dificuldade, sel.Categoria))
+	case sel.Modo == ModoPontosFracos:
+		p.questoes, p.total = q.atenderPedidos(ctx, q.pedidosPontosFracos(quantidade, dificuldade))
+	case !q.usarIA:
+		p.questoes, p.total = canalCompleto(q.sortearQuestoes(quantidade, dificuldade, sel.Categoria, sel.Tipo))
+	default:
+		p.questoes, p.total = q.atenderPedidos(ctx, montarPedidos(quantidade, dificuldade, sel.Categoria, sel.Tipo))
+	}
+	return p
+}
+
+// atenderPedidos gera as questões pedidas em segundo plano, substituindo
+// cada falha por uma questão do banco. Sem IA, busca todas no banco.
+func (q *Quiz) atenderPedidos(ctx context.Context, pedidos []PedidoQuestao) (<-chan Questao, int) {
+	reserva := NovoGeradorEstatico(q.questoes)
+	if !q.usarIA {
+		var questoes []Questao
+		for _, pedido := range pedidos {
+			if questao, err := reserva.Gerar(ctx, pedido); err == nil {
+				questoes = append(questoes, *questao)
+			}
+		}
+		return canalCompleto(questoes)
+	}
+
+	// O canal comporta todas as questões para que a geração nunca fique
+	// presa esperando o jogador.
+	canal := make(chan Questao, len(pedidos))
+	go func() {
+		defer close(canal)
+
+		var geradas []Questao
+		for res := range GerarEmParalelo(ctx, q.gerador, pedidos, q.opcoesGeracao()) {
+			questao := res.Questao
+			if res.Err != nil {
+				// Usar questão do banco com a mesma dificuldade e categoria
+				var err error
+				if questao, err = reserva.Gerar(context.Background(), res.Pedido); err != nil {
+					continue
+				}
+			} else {
+				geradas = append(geradas, *questao)
+			}
+			canal <- *questao
+		}
+
+		// O quiz está na tela; uma falha ao salvar só faz as questões
+		// desta sessão não ficarem disponíveis offline.
+		_ = q.guardarGeradas(geradas...)
+	}()
+
+	return canal, len(pedidos)
+}
+
+// canalCompleto entrega questões já escolhidas pelo mesmo canal da geração.
+func canalCompleto(questoes []Questao) (<-chan Questao, int) {
+	canal := make(chan Questao, len(questoes))
+	for _, questao := range questoes {
+		canal <- questao
+	}
+	close(canal)
+	return canal, len(questoes)
+}
+
+// questoesRevisao retorna até quantidade questões com revisão marcada até
+// hoje, das mais atrasadas para as mais recentes. Questões geradas pela IA
+// guardadas no acervo também entram, mesmo com a IA ativa.
+func (q *Quiz) questoesRevisao(quantidade int, dificuldade, categoria string) []Questao {
+	porID := make(map[int]Questao, len(q.questoes))
+	if q.acervo != nil {
+		for _, questao := range q.acervo.Questoes() {
+			porID[questao.ID] = questao
+		}
+	}
+	for _, questao := range q.questoes {
+		porID[questao.ID] = questao
+	}
+
+	var questoes []Questao
+	for _, id := range q.stats.ParaRevisar(time.Now()) {
+		questao, ok := porID[id]
+		if !ok {
+			continue
+		}
+		if dificuldade != "" && questao.Dificuldade != dificuldade {
+			continue
+		}
+		if categoria != "" && !strings.EqualFold(questao.Categoria, categoria) {
+			continue
+		}
+		questoes = append(questoes, questao)
+		if len(questoes) == quantidade {
+			break
+		}
+	}
+	return questoes
+}
+
+// RevisoesPendentes conta as questões com revisão marcada até hoje.
+func (q *Quiz) RevisoesPendentes() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.questoesRevisao(len(q.stats.Revisoes), "", ""))
+}
+
+func contem(lista []string, valor string) bool {
+	for _, item := range lista {
+		if item == valor {
+			return true
+		}
+	}
+	return false
+}
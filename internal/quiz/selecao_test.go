@@ -0,0 +1,131 @@
+package quiz
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"quiz_go/internal/config"
+	"quiz_go/internal/ollamafalso"
+)
+
+// novoQuizTeste cria um quiz que usa o servidor falso e grava seus arquivos
+// num diretório temporário. ajustar muda a configuração antes da criação.
+func novoQuizTeste(t *testing.T, s *ollamafalso.Servidor, ajustar ...func(*config.Config)) *Quiz {
+	t.Helper()
+	dir := t.TempDir()
+
+	cfg := config.Padrao()
+	cfg.Gerador = GeradorNomeOllama
+	cfg.OllamaURL = s.URL
+	cfg.TrabalhadoresGeracao = 1 // as requisições chegam ao servidor na ordem dos pedidos
+	cfg.IntervaloGeracao = 0
+	cfg.TimeoutGeracao = 500 * time.Millisecond
+	cfg.ArquivoStats = filepath.Join(dir, "stats.json")
+	cfg.ArquivoGeradas = filepath.Join(dir, "geradas.json")
+	cfg.ArquivoAutoverificacao = filepath.Join(dir, "autoverificacao.json")
+	for _, f := range ajustar {
+		f(&cfg)
+	}
+	return NewQuizComCliente(cfg, s.Client())
+}
+
+func TestNewQuizSemIAUsaOBanco(t *testing.T) {
+	s := ollamafalso.Novo(t)
+	s.Close()
+
+	q := novoQuizTeste(t, s)
+	if q.IAAtiva() {
+		t.Fatal("a IA não deveria estar ativa com o servidor fora do ar")
+	}
+
+	canal, total := q.atenderPedidos(context.Background(), montarPedidos(3, "medio", "tipos", ""))
+	if total != 3 {
+		t.Fatalf("total = %d, esperado 3", total)
+	}
+	for questao := range canal {
+		if questao.Dificuldade != "medio" || questao.Categoria != "tipos" {
+			t.Errorf("questão %d é %s/%s, esperado medio/tipos", questao.ID, questao.Dificuldade, questao.Categoria)
+		}
+	}
+}
+
+func TestAtenderPedidosSubstituiFalhasPeloBanco(t *testing.T) {
+	s := ollamafalso.Novo(t,
+		ollamafalso.JSON(questaoValida()),
+		ollamafalso.Erro(500, "falha interna"),
+		ollamafalso.Malformado("<html>proxy</html>"),
+		ollamafalso.Lento(time.Minute, ollamafalso.JSON(questaoValida())),
+	)
+	q := novoQuizTeste(t, s)
+	if !q.IAAtiva() {
+		t.Fatal("a IA deveria estar ativa")
+	}
+
+	canal, total := q.atenderPedidos(context.Background(), montarPedidos(4, "facil", "sintaxe", ""))
+	var questoes []Questao
+	for questao := range canal {
+		questoes = append(questoes, questao)
+	}
+	if len(questoes) != total || total != 4 {
+		t.Fatalf("%d questões de %d, esperado 4", len(questoes), total)
+	}
+
+	if questoes[0].ID < PrimeiroIDGerado || questoes[0].Resposta != "len" {
+		t.Errorf("a primeira questão deveria ser a gerada, veio %d", questoes[0].ID)
+	}
+	for _, questao := range questoes[1:] {
+		if questao.ID >= PrimeiroIDGerado {
+			t.Errorf("questão %d deveria ter vindo do banco", questao.ID)
+		}
+		if questao.Dificuldade != "facil" || questao.Categoria != "sintaxe" {
+			t.Errorf("questão do banco %d é %s/%s, esperado facil/sintaxe", questao.ID, questao.Dificuldade, questao.Categoria)
+		}
+	}
+
+	if n := len(q.acervo.Questoes()); n != 1 {
+		t.Errorf("%d questões no acervo, esperado só a gerada", n)
+	}
+}
+
+func TestGerarQuestoesIARelataFalhas(t *testing.T) {
+	s := ollamafalso.Novo(t, ollamafalso.JSON(questaoValida()), ollamafalso.Erro(503, "ocupado"))
+	q := novoQuizTeste(t, s)
+
+	questoes, err := q.GerarQuestoesIA(context.Background(), 2, "facil", "sintaxe", "")
+	if len(questoes) != 1 {
+		t.Errorf("%d questões, esperado 1", len(questoes))
+	}
+	if err == nil || !strings.Contains(err.Error(), "questão 2") || !strings.Contains(err.Error(), "status 503") {
+		t.Errorf("erro = %v, esperado a falha da questão 2", err)
+	}
+}
+
+func TestAutoverificacaoDescartaDiscordancia(t *testing.T) {
+	s := ollamafalso.Novo(t,
+		ollamafalso.JSON(questaoValida()),
+		ollamafalso.JSON(map[string]string{"resposta": "len"}),
+		ollamafalso.JSON(questaoValida()),
+		ollamafalso.JSON(map[string]string{"resposta": "cap"}),
+	)
+	q := novoQuizTeste(t, s, func(cfg *config.Config) { cfg.Autoverificacao = AutoverificacaoLigada })
+
+	questoes, err := q.GerarQuestoesIA(context.Background(), 2, "facil", "sintaxe", "")
+	if len(questoes) != 1 {
+		t.Errorf("%d questões, esperado 1", len(questoes))
+	}
+	if err == nil || !strings.Contains(err.Error(), "respondeu 'cap'") {
+		t.Errorf("erro = %v, esperado a discordância", err)
+	}
+
+	registro, err := AbrirRegistroAutoverificacao(q.config.ArquivoAutoverificacao)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pares := registro.Concordancias()
+	if len(pares) != 1 || pares[0].Concordou != 1 || pares[0].Discordou != 1 {
+		t.Errorf("concordâncias gravadas = %+v, esperado 1 e 1", pares)
+	}
+}
@@ -0,0 +1,312 @@
+package quiz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"quiz_go/internal/stats"
+)
+
+// ErrSemQuestao indica uma resposta sem questão mostrada aguardando por ela.
+var ErrSemQuestao = errors.New("nenhuma questão aguardando resposta")
+
+// Sessao conduz um quiz sem entrada nem saída: entrega as questões, corrige
+// as respostas, acompanha os prazos e a pontuação e, no fim, atualiza as
+// estatísticas. Quem mostra as questões e lê as respostas é o frontend;
+// veja Jogar.
+type Sessao struct {
+	quiz        *Quiz
+	modo        Modo
+	dificuldade string
+	questoes    <-chan Questao
+	total       int
+	id          string
+	cronometro  *cronometro
+	prova       time.Duration
+
+	// retorno recebe as respostas, no modo adaptativo.
+	retorno chan<- stats.Resposta
+
+	inicio            time.Time
+	habilidadeInicial float64
+
+	atual         Questao
+	inicioQuestao time.Time
+	aguardando    bool // a questão atual ainda não foi respondida
+	entregues     int
+	descartadas   int // entregues sem resposta lida; ficam fora dos totais
+	acertos       int
+	pontos        float64
+	respostas     []stats.Resposta
+}
+
+// Resultado é a correção de uma questão.
+type Resultado struct {
+	Questao    Questao
+	Numero     int // posição da questão no quiz, a partir de 1
+	Escolhidas []string
+	Correta    bool
+	Esgotado   bool // o tempo acabou antes da resposta
+	Duracao    time.Duration
+	Acertos    int // acertos no quiz até esta questão
+}
+
+// Resumo é o resultado final de um quiz.
+type Resumo struct {
+	Acertos     int
+	Respondidas int
+	SemResposta int // questões da prova não entregues quando o tempo acabou; contam em Respondidas
+	Respostas   []stats.Resposta
+	Pontos      float64 // acertos somados ao bônus de velocidade
+	Bonus       bool    // o bônus de velocidade está ligado
+	Tempo       time.Duration
+
+	// HabilidadeAnterior e Habilidade são a habilidade estimada do jogador
+	// antes e depois do quiz.
+	HabilidadeAnterior float64
+	Habilidade         float64
+
+	// Modo e Dificuldade identificam o quadro de recordes do quiz; veja
+	// Quadro. Recorde indica que a partida ficou à frente de todas as do
+	// quadro, de todos os perfis, e RecordePessoal, à frente das do próprio
+	// jogador. Os dois ficam falsos na primeira partida do quadro.
+	Modo           Modo
+	Dificuldade    string
+	Recorde        bool
+	RecordePessoal bool
+
+	// ErroEstatisticas é a falha ao salvar as estatísticas do quiz, se houve.
+	ErroEstatisticas error
+}
+
+// Percentual retorna a porcentagem de acertos entre as questões respondidas.
+func (r Resumo) Percentual() float64 {
+	if r.Respondidas == 0 {
+		return 0
+	}
+	return float64(r.Acertos) / float64(r.Respondidas) * 100
+}
+
+// SemTempo conta as questões em que o tempo acabou antes da resposta.
+func (r Resumo) SemTempo() int {
+	n := 0
+	for _, resposta := range r.Respostas {
+		if resposta.TempoEsgotado {
+			n++
+		}
+	}
+	return n
+}
+
+// IniciarSessao começa a montar as questões de uma seleção (veja
+// IniciarQuestoes) e retorna a sessão que as entrega. Cancelar ctx
+// interrompe a geração. Várias sessões podem estar em andamento ao mesmo
+// tempo, mas cada uma só pode ser usada por uma goroutine de cada vez.
+func (q *Quiz) IniciarSessao(ctx context.Context, sel Selecao) *Sessao {
+	return q.novaSessao(q.preparar(ctx, sel))
+}
+
+func (q *Quiz) novaSessao(p preparo) *Sessao {
+	return &Sessao{
+		quiz:              q,
+		modo:              p.modo,
+		dificuldade:       p.dificuldade,
+		questoes:          p.questoes,
+		total:             p.total,
+		id:                stats.NovaSessao(),
+		cronometro:        novoCronometro(q.config.TempoQuestao, p.prova),
+		prova:             p.prova,
+		retorno:           p.retorno,
+		inicio:            time.Now(),
+		habilidadeInicial: q.habilidade(),
+	}
+}
+
+// Total retorna o número esperado de questões. O quiz termina antes se a
+// geração não conseguir entregar todas.
+func (s *Sessao) Total() int {
+	return s.total
+}
+
+// DuracaoProva retorna o tempo total do quiz no modo Prova, ou zero.
+func (s *Sessao) DuracaoProva() time.Duration {
+	return s.prova
+}
+
+// Numero retorna a posição da questão atual, a partir de 1.
+func (s *Sessao) Numero() int {
+	return s.entregues
+}
+
+// Restantes retorna quantas questões ainda não foram entregues.
+func (s *Sessao) Restantes() int {
+	return max(0, s.total-s.entregues)
+}
+
+// Proxima entrega a próxima questão, esperando a geração se ela ainda não
+// estiver pronta. Enquanto a questão atual não for respondida, ela é
+// entregue de novo. ok é falso quando não há mais questões.
+func (s *Sessao) Proxima() (questao Questao, ok bool) {
+	if s.aguardando {
+		return s.atual, true
+	}
+	inicioEspera := time.Now()
+	questao, ok = <-s.questoes
+	return s.mostrar(questao, ok, time.Since(inicioEspera))
+}
+
+// ProximaPronta é como Proxima, mas não espera: pronta é falso se a
+// próxima questão ainda está sendo gerada.
+func (s *Sessao) ProximaPronta() (questao Questao, ok, pronta bool) {
+	if s.aguardando {
+		return s.atual, true, true
+	}
+	select {
+	case questao, ok = <-s.questoes:
+		questao, ok = s.mostrar(questao, ok, 0)
+		return questao, ok, true
+	default:
+		return Questao{}, false, false
+	}
+}
+
+// mostrar marca a questão recebida como atual. A espera pela geração não
+// conta no tempo da prova.
+func (s *Sessao) mostrar(questao Questao, ok bool, espera time.Duration) (Questao, bool) {
+	if !ok {
+		return Questao{}, false
+	}
+	s.cronometro.pausar(espera)
+	s.entregues++
+	s.atual = questao
+	s.inicioQuestao = time.Now()
+	s.aguardando = true
+	return questao, true
+}
+
+// Prazo retorna até quando a questão atual pode ser respondida, ou zero se
+// não houver limite.
+func (s *Sessao) Prazo() time.Time {
+	return s.cronometro.prazoQuestao(s.inicioQuestao)
+}
+
+// FimProva retorna quando acaba o tempo da prova, ou zero fora do modo Prova.
+func (s *Sessao) FimProva() time.Time {
+	return s.cronometro.fimProva
+}
+
+// ProvaEncerrada informa se o tempo da prova acabou.
+func (s *Sessao) ProvaEncerrada() bool {
+	return s.cronometro.provaEncerrada()
+}
+
+// Responder corrige a resposta à questão atual: as opções marcadas ou o
+// texto digitado. Uma resposta que chega depois do prazo conta como tempo
+// esgotado.
+func (s *Sessao) Responder(escolhidas []string) (Resultado, error) {
+	if !s.aguardando {
+		return Resultado{}, ErrSemQuestao
+	}
+	if prazo := s.Prazo(); !prazo.IsZero() && time.Since(prazo) > toleranciaPrazo {
+		return s.corrigir(nil, true), nil
+	}
+	return s.corrigir(escolhidas, false), nil
+}
+
+// EsgotarTempo registra a questão atual como não respondida a tempo.
+func (s *Sessao) EsgotarTempo() (Resultado, error) {
+	if !s.aguardando {
+		return Resultado{}, ErrSemQuestao
+	}
+	return s.corrigir(nil, true), nil
+}
+
+// Descartar passa para a próxima questão sem registrar a atual, quando não
+// foi possível ler a resposta do jogador.
+func (s *Sessao) Descartar() {
+	if !s.aguardando {
+		return
+	}
+	s.aguardando = false
+	s.descartadas++
+	s.informar(stats.Resposta{QuestaoID: s.atual.ID})
+}
+
+func (s *Sessao) corrigir(escolhidas []string, esgotado bool) Resultado {
+	duracao := time.Since(s.inicioQuestao)
+	correta := !esgotado && s.atual.Corrigir(escolhidas)
+	registro := stats.Resposta{
+		QuestaoID:     s.atual.ID,
+		Categoria:     s.atual.Categoria,
+		Dificuldade:   s.atual.Dificuldade,
+		Escolhida:     strings.Join(escolhidas, "; "),
+		Correta:       correta,
+		DuracaoMs:     duracao.Milliseconds(),
+		Momento:       s.inicioQuestao,
+		Sessao:        s.id,
+		TempoEsgotado: esgotado,
+	}
+	s.respostas = append(s.respostas, registro)
+	s.aguardando = false
+	s.informar(registro)
+
+	if correta {
+		s.acertos++
+		s.pontos += 1 + s.cronometro.bonus(s.quiz.config.BonusVelocidade, duracao)
+	}
+	return Resultado{
+		Questao:    s.atual,
+		Numero:     s.entregues,
+		Escolhidas: escolhidas,
+		Correta:    correta,
+		Esgotado:   esgotado,
+		Duracao:    duracao,
+		Acertos:    s.acertos,
+	}
+}
+
+// informar avisa a seleção adaptativa, se ativa, que a questão foi
+// respondida. Uma resposta sem Escolhida e sem TempoEsgotado indica que não
+// foi possível ler a resposta do jogador.
+func (s *Sessao) informar(r stats.Resposta) {
+	if s.retorno != nil {
+		s.retorno <- r
+	}
+}
+
+// Interromper encerra o quiz antes do fim. Ele não conta nos totais, mas as
+// respostas ficam no histórico.
+func (s *Sessao) Interromper() {
+	s.quiz.registrarRespostas(s.respostas)
+}
+
+// Encerrar termina o quiz, atualiza as estatísticas e retorna o resumo.
+func (s *Sessao) Encerrar() Resumo {
+	// As questões que o fim da prova deixou sem resposta contam como
+	// erros, como em uma prova de papel.
+	semResposta := 0
+	if s.ProvaEncerrada() {
+		semResposta = s.Restantes()
+	}
+	resumo := Resumo{
+		Acertos:            s.acertos,
+		Respondidas:        s.entregues - s.descartadas + semResposta,
+		SemResposta:        semResposta,
+		Respostas:          s.respostas,
+		Pontos:             s.pontos,
+		Bonus:              s.quiz.config.BonusVelocidade > 0,
+		Tempo:              time.Since(s.inicio),
+		HabilidadeAnterior: s.habilidadeInicial,
+		Modo:               s.modo,
+		Dificuldade:        s.dificuldade,
+	}
+	if len(s.respostas) > 0 {
+		partida := stats.NovaPartida(s.id, string(s.modo), s.dificuldade, resumo.Respondidas, s.respostas, time.Now())
+		resumo.Recorde, resumo.RecordePessoal = s.quiz.registrarPartida(partida)
+	}
+	resumo.ErroEstatisticas = s.quiz.AtualizarEstatisticas(s.acertos, resumo.Respondidas, s.respostas)
+	resumo.Habilidade = s.quiz.habilidade()
+	return resumo
+}
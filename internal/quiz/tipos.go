@@ -0,0 +1,205 @@
+package quiz
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Tipos de questão. Questões sem Tipo são de múltipla escolha.
+const (
+	TipoMultiplaEscolha    = "multipla_escolha"    // uma opção correta, em Resposta
+	TipoMultiplasRespostas = "multiplas_respostas" // uma ou mais opções corretas, em Respostas
+	TipoVerdadeiroFalso    = "verdadeiro_falso"    // Resposta é Verdadeiro ou Falso, sem Opcoes
+	TipoTextoLivre         = "texto"               // resposta curta digitada, comparada com Resposta e Alternativas
+	TipoSaidaCodigo        = "saida_codigo"        // o que Codigo imprime, digitado ou escolhido entre Opcoes
+)
+
+// Tipos lista os tipos de questão aceitos.
+var Tipos = []string{TipoMultiplaEscolha, TipoMultiplasRespostas, TipoVerdadeiroFalso, TipoTextoLivre, TipoSaidaCodigo}
+
+// Opções das questões de verdadeiro ou falso.
+const (
+	Verdadeiro = "Verdadeiro"
+	Falso      = "Falso"
+)
+
+// TipoQuestao retorna o tipo da questão, considerando o padrão.
+func (q Questao) TipoQuestao() string {
+	if q.Tipo == "" {
+		return TipoMultiplaEscolha
+	}
+	return q.Tipo
+}
+
+// OpcoesExibidas retorna as opções mostradas ao jogador: Verdadeiro e Falso
+// nas questões desse tipo e Opcoes nas demais.
+func (q Questao) OpcoesExibidas() []string {
+	if q.TipoQuestao() == TipoVerdadeiroFalso {
+		return []string{Verdadeiro, Falso}
+	}
+	return q.Opcoes
+}
+
+// RespostaDigitada informa se o jogador digita a resposta em vez de
+// escolher uma opção.
+func (q Questao) RespostaDigitada() bool {
+	switch q.TipoQuestao() {
+	case TipoTextoLivre:
+		return true
+	case TipoSaidaCodigo:
+		return len(q.Opcoes) == 0
+	}
+	return false
+}
+
+// Corrigir confere a resposta do jogador. Nas questões de múltiplas
+// respostas, só é acerto marcar exatamente as opções corretas. Respostas
+// digitadas são comparadas com Resposta e Alternativas: texto livre ignora
+// caixa, acentos, espaços e pontuação final; saída de código só ignora a
+// quantidade de espaços e quebras de linha.
+func (q Questao) Corrigir(escolhidas []string) bool {
+	switch {
+	case q.TipoQuestao() == TipoMultiplasRespostas:
+		marcadas := make([]string, len(escolhidas))
+		for i, escolhida := range escolhidas {
+			marcadas[i] = strings.TrimSpace(escolhida)
+		}
+		corretas := make([]string, len(q.Respostas))
+		for i, resposta := range q.Respostas {
+			corretas[i] = strings.TrimSpace(resposta)
+		}
+		slices.Sort(marcadas)
+		slices.Sort(corretas)
+		return slices.Equal(slices.Compact(marcadas), slices.Compact(corretas))
+	case len(escolhidas) != 1:
+		return false
+	case q.TipoQuestao() == TipoVerdadeiroFalso:
+		return strings.EqualFold(strings.TrimSpace(escolhidas[0]), strings.TrimSpace(q.Resposta))
+	case q.RespostaDigitada():
+		normalizar := normalizarTexto
+		if q.TipoQuestao() == TipoSaidaCodigo {
+			normalizar = normalizarSaida
+		}
+		digitada := normalizar(escolhidas[0])
+		for _, aceita := range append([]string{q.Resposta}, q.Alternativas...) {
+			if digitada == normalizar(aceita) {
+				return true
+			}
+		}
+		return false
+	default:
+		return strings.TrimSpace(escolhidas[0]) == strings.TrimSpace(q.Resposta)
+	}
+}
+
+// RespostaEsperada descreve a resposta correta para mostrar ao jogador.
+func (q Questao) RespostaEsperada() string {
+	if q.TipoQuestao() == TipoMultiplasRespostas {
+		return strings.Join(q.Respostas, "; ")
+	}
+	return q.Resposta
+}
+
+// normalizarTexto prepara uma resposta curta para comparação.
+func normalizarTexto(texto string) string {
+	return normalizar(semAcentos(strings.Trim(strings.TrimSpace(texto), "`\"'")))
+}
+
+// semAcentos remove os acentos e outras marcas combinantes do texto.
+func semAcentos(texto string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(texto) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// normalizarSaida prepara a saída de um programa para comparação. Como a
+// resposta é digitada numa linha só, quebras de linha valem como espaços.
+func normalizarSaida(texto string) string {
+	return strings.Join(strings.Fields(texto), " ")
+}
+
+func validarQuestao(questao *QuestaoGerada) error {
+	if strings.TrimSpace(questao.Questao) == "" {
+		return fmt.Errorf("questão vazia")
+	}
+
+	switch questao.Tipo {
+	case "", TipoMultiplaEscolha:
+		return validarOpcoes(questao.Opcoes, questao.Resposta)
+
+	case TipoMultiplasRespostas:
+		if len(questao.Opcoes) < 4 || len(questao.Opcoes) > 6 {
+			return fmt.Errorf("deve ter de 4 a 6 opções, encontradas: %d", len(questao.Opcoes))
+		}
+		if len(questao.Respostas) == 0 {
+			return fmt.Errorf("nenhuma resposta correta em \"respostas\"")
+		}
+		if len(questao.Respostas) >= len(questao.Opcoes) {
+			return fmt.Errorf("pelo menos uma opção deve estar errada")
+		}
+		for _, resposta := range questao.Respostas {
+			if !contemOpcao(questao.Opcoes, resposta) {
+				return fmt.Errorf("resposta '%s' não encontrada nas opções", resposta)
+			}
+		}
+		return nil
+
+	case TipoVerdadeiroFalso:
+		if !strings.EqualFold(strings.TrimSpace(questao.Resposta), Verdadeiro) &&
+			!strings.EqualFold(strings.TrimSpace(questao.Resposta), Falso) {
+			return fmt.Errorf("resposta deve ser '%s' ou '%s', encontrada: '%s'", Verdadeiro, Falso, questao.Resposta)
+		}
+		return nil
+
+	case TipoTextoLivre:
+		if len(questao.Opcoes) > 0 {
+			return fmt.Errorf("questões de texto livre não têm opções")
+		}
+		if strings.TrimSpace(questao.Resposta) == "" {
+			return fmt.Errorf("resposta vazia")
+		}
+		return nil
+
+	case TipoSaidaCodigo:
+		if strings.TrimSpace(questao.Codigo) == "" {
+			return fmt.Errorf("código vazio")
+		}
+		if len(questao.Opcoes) > 0 {
+			return validarOpcoes(questao.Opcoes, questao.Resposta)
+		}
+		if strings.TrimSpace(questao.Resposta) == "" {
+			return fmt.Errorf("resposta vazia")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("tipo '%s' desconhecido (use %s)", questao.Tipo, strings.Join(Tipos, ", "))
+}
+
+// validarOpcoes exige exatamente 4 opções, uma delas a resposta.
+func validarOpcoes(opcoes []string, resposta string) error {
+	if len(opcoes) != 4 {
+		return fmt.Errorf("deve ter exatamente 4 opções, encontradas: %d", len(opcoes))
+	}
+	if !contemOpcao(opcoes, resposta) {
+		return fmt.Errorf("resposta '%s' não encontrada nas opções", resposta)
+	}
+	return nil
+}
+
+func contemOpcao(opcoes []string, resposta string) bool {
+	for _, opcao := range opcoes {
+		if strings.TrimSpace(opcao) == strings.TrimSpace(resposta) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package quiz
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidarQuestao(t *testing.T) {
+	opcoes := []string{"a", "b", "c", "d"}
+	casos := []struct {
+		nome    string
+		questao QuestaoGerada
+		erro    string // vazio quando a questão é válida
+	}{
+		{"múltipla escolha", QuestaoGerada{Questao: "q", Opcoes: opcoes, Resposta: "b"}, ""},
+		{"resposta com espaços", QuestaoGerada{Questao: "q", Opcoes: opcoes, Resposta: " b "}, ""},
+		{"enunciado vazio", QuestaoGerada{Questao: "  ", Opcoes: opcoes, Resposta: "b"}, "questão vazia"},
+		{"três opções", QuestaoGerada{Questao: "q", Opcoes: opcoes[:3], Resposta: "b"}, "exatamente 4 opções"},
+		{"resposta fora das opções", QuestaoGerada{Questao: "q", Opcoes: opcoes, Resposta: "e"}, "não encontrada"},
+		{"tipo desconhecido", QuestaoGerada{Tipo: "dissertativa", Questao: "q"}, "tipo 'dissertativa' desconhecido"},
+
+		{"múltiplas respostas", QuestaoGerada{Tipo: TipoMultiplasRespostas, Questao: "q", Opcoes: opcoes, Respostas: []string{"a", "c"}}, ""},
+		{"múltiplas sem respostas", QuestaoGerada{Tipo: TipoMultiplasRespostas, Questao: "q", Opcoes: opcoes}, "nenhuma resposta correta"},
+		{"múltiplas todas corretas", QuestaoGerada{Tipo: TipoMultiplasRespostas, Questao: "q", Opcoes: opcoes, Respostas: opcoes}, "pelo menos uma opção deve estar errada"},
+		{"múltiplas com sete opções", QuestaoGerada{Tipo: TipoMultiplasRespostas, Questao: "q", Opcoes: strings.Split("abcdefg", ""), Respostas: []string{"a"}}, "de 4 a 6 opções"},
+
+		{"verdadeiro", QuestaoGerada{Tipo: TipoVerdadeiroFalso, Questao: "q", Resposta: "verdadeiro"}, ""},
+		{"verdadeiro ou falso inválido", QuestaoGerada{Tipo: TipoVerdadeiroFalso, Questao: "q", Resposta: "talvez"}, "Verdadeiro' ou 'Falso"},
+
+		{"texto", QuestaoGerada{Tipo: TipoTextoLivre, Questao: "q", Resposta: "len"}, ""},
+		{"texto com opções", QuestaoGerada{Tipo: TipoTextoLivre, Questao: "q", Opcoes: opcoes, Resposta: "a"}, "não têm opções"},
+		{"texto sem resposta", QuestaoGerada{Tipo: TipoTextoLivre, Questao: "q"}, "resposta vazia"},
+
+		{"saída digitada", QuestaoGerada{Tipo: TipoSaidaCodigo, Questao: "q", Codigo: "package main", Resposta: "1"}, ""},
+		{"saída com opções", QuestaoGerada{Tipo: TipoSaidaCodigo, Questao: "q", Codigo: "package main", Opcoes: opcoes, Resposta: "c"}, ""},
+		{"saída sem código", QuestaoGerada{Tipo: TipoSaidaCodigo, Questao: "q", Resposta: "1"}, "código vazio"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			err := validarQuestao(&c.questao)
+			switch {
+			case c.erro == "" && err != nil:
+				t.Errorf("erro inesperado: %v", err)
+			case c.erro != "" && (err == nil || !strings.Contains(err.Error(), c.erro)):
+				t.Errorf("erro = %v, esperado conter %q", err, c.erro)
+			}
+		})
+	}
+}
+
+func TestCorrigir(t *testing.T) {
+	opcoes := []string{"len", "cap", "make", "new"}
+	escolha := Questao{Opcoes: opcoes, Resposta: "len"}
+	multiplas := Questao{Tipo: TipoMultiplasRespostas, Opcoes: opcoes, Respostas: []string{"len", "cap"}}
+	vf := Questao{Tipo: TipoVerdadeiroFalso, Resposta: Verdadeiro}
+	texto := Questao{Tipo: TipoTextoLivre, Resposta: "Goroutine", Alternativas: []string{"go routine"}}
+	acentos := Questao{Tipo: TipoTextoLivre, Resposta: "coleta de lixo"}
+	saida := Questao{Tipo: TipoSaidaCodigo, Codigo: "package main", Resposta: "1 2\n3"}
+	saidaOpcoes := Questao{Tipo: TipoSaidaCodigo, Codigo: "package main", Opcoes: opcoes, Resposta: "cap"}
+
+	casos := []struct {
+		nome       string
+		questao    Questao
+		escolhidas []string
+		correta    bool
+	}{
+		{"múltipla escolha certa", escolha, []string{"len"}, true},
+		{"múltipla escolha com espaços", escolha, []string{" len "}, true},
+		{"múltipla escolha errada", escolha, []string{"cap"}, false},
+		{"múltipla escolha com duas marcadas", escolha, []string{"len", "cap"}, false},
+		{"múltipla escolha sem resposta", escolha, nil, false},
+		{"múltipla escolha diferencia caixa", escolha, []string{"LEN"}, false},
+
+		{"múltiplas na ordem", multiplas, []string{"len", "cap"}, true},
+		{"múltiplas fora de ordem", multiplas, []string{"cap", "len"}, true},
+		{"múltiplas repetidas", multiplas, []string{"cap", "len", "cap"}, true},
+		{"múltiplas faltando uma", multiplas, []string{"len"}, false},
+		{"múltiplas com uma a mais", multiplas, []string{"len", "cap", "new"}, false},
+		{"múltiplas sem resposta", multiplas, nil, false},
+
+		{"verdadeiro", vf, []string{Verdadeiro}, true},
+		{"verdadeiro em minúsculas", vf, []string{" verdadeiro "}, true},
+		{"falso", vf, []string{Falso}, false},
+
+		{"texto exato", texto, []string{"Goroutine"}, true},
+		{"texto em outra caixa e com pontuação", texto, []string{"  GOROUTINE. "}, true},
+		{"texto entre crases", texto, []string{"`goroutine`"}, true},
+		{"texto alternativo", texto, []string{"Go  Routine"}, true},
+		{"texto errado", texto, []string{"thread"}, false},
+		{"texto sem acentos", acentos, []string{"Coleta de lixo"}, true},
+		{"texto com acentos", Questao{Tipo: TipoTextoLivre, Resposta: "função"}, []string{"FUNCAO"}, true},
+		{"texto com acento a mais", acentos, []string{"coléta de lixo!"}, true},
+
+		{"saída exata", saida, []string{"1 2\n3"}, true},
+		{"saída em uma linha", saida, []string{"1  2 3"}, true},
+		{"saída diferencia caixa e pontuação", Questao{Tipo: TipoSaidaCodigo, Codigo: "package main", Resposta: "Ok."}, []string{"ok"}, false},
+		{"saída com opções", saidaOpcoes, []string{"cap"}, true},
+		{"saída com opções errada", saidaOpcoes, []string{"len"}, false},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			if got := c.questao.Corrigir(c.escolhidas); got != c.correta {
+				t.Errorf("Corrigir(%q) = %v, esperado %v", c.escolhidas, got, c.correta)
+			}
+		})
+	}
+}
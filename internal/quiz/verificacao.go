@@ -0,0 +1,235 @@
+package quiz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Modos aceitos na chave "verificacao_codigo" da configuração.
+const (
+	VerificacaoCorrigir  = "corrigir"  // troca uma resposta errada pela saída real
+	VerificacaoRejeitar  = "rejeitar"  // descarta a questão com resposta errada
+	VerificacaoDesligada = "desligada" // não executa o código
+)
+
+const (
+	// limiteSaidaCodigo limita a saída guardada de um programa verificado.
+	limiteSaidaCodigo = 64 << 10
+
+	// versaoGoVerificacao vai no go.mod do programa verificado. A partir da
+	// 1.22, cada iteração do for tem a sua variável, como nas versões atuais.
+	versaoGoVerificacao = "1.22"
+)
+
+// importsPermitidos são os únicos pacotes que o programa pode importar: os
+// de cálculo e texto da biblioteca padrão, que não abrem arquivos nem
+// conexões. Os programas das questões só precisam imprimir alguma coisa.
+var importsPermitidos = []string{
+	"bufio", "bytes", "cmp", "container/heap", "container/list", "container/ring", "context",
+	"encoding/json", "errors", "fmt", "io", "iter", "maps", "math", "math/bits", "math/cmplx",
+	"math/rand", "math/rand/v2", "reflect", "regexp", "runtime", "slices", "sort", "strconv",
+	"strings", "sync", "sync/atomic", "time", "unicode", "unicode/utf16", "unicode/utf8",
+}
+
+// ErrSemIsolamento indica que o sistema não permite executar o programa sem
+// acesso à rede, e por isso ele não foi executado.
+var ErrSemIsolamento = errors.New("não é possível executar o código isolado da rede neste sistema")
+
+// ExecutorGo compila e executa programas Go curtos num diretório temporário,
+// sem rede e com tempo limite. Só os pacotes de importsPermitidos podem ser
+// importados. O programa roda num espaço de rede próprio e vazio (unshare,
+// no Linux); onde isso não é possível, nada é executado.
+type ExecutorGo struct {
+	Timeout time.Duration // tempo limite para compilar e executar; 0 usa só ctx
+}
+
+// GoDisponivel informa se a ferramenta go está no PATH.
+func GoDisponivel() bool {
+	_, err := exec.LookPath("go")
+	return err == nil
+}
+
+// Executar compila e executa codigo e retorna o que ele escreveu na saída
+// padrão. Um programa que não compila, termina com erro ou passa do tempo
+// limite resulta em erro. Compilar e executar são passos separados, em vez
+// de "go run", para que o tempo limite encerre o próprio programa e não só
+// o comando go. Sem isolamento de rede, retorna ErrSemIsolamento.
+func (e *ExecutorGo) Executar(ctx context.Context, codigo string) (string, error) {
+	if err := conferirPrograma(codigo); err != nil {
+		return "", err
+	}
+	if !IsolamentoDisponivel() {
+		return "", ErrSemIsolamento
+	}
+
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.Timeout)
+		defer cancel()
+	}
+
+	dir, err := os.MkdirTemp("", "quiz_go_codigo_")
+	if err != nil {
+		return "", fmt.Errorf("erro ao criar diretório temporário: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	arquivos := map[string]string{
+		"go.mod":  "module codigo\n\ngo " + versaoGoVerificacao + "\n",
+		"main.go": codigo,
+	}
+	for nome, conteudo := range arquivos {
+		if err := os.WriteFile(filepath.Join(dir, nome), []byte(conteudo), 0644); err != nil {
+			return "", fmt.Errorf("erro ao gravar %s: %v", nome, err)
+		}
+	}
+
+	programa := filepath.Join(dir, "programa")
+	compilar := exec.CommandContext(ctx, "go", "build", "-o", programa, ".")
+	compilar.Dir = dir
+	compilar.Env = append(os.Environ(), "GOPROXY=off", "GOFLAGS=-mod=mod", "GOTOOLCHAIN=local", "GOWORK=off", "CGO_ENABLED=0")
+	if saida, err := compilar.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("tempo limite excedido ao compilar o código")
+		}
+		return "", fmt.Errorf("o código não compila: %s", resumir(string(saida), tamanhoConceito))
+	}
+
+	stdout := &saidaLimitada{limite: limiteSaidaCodigo}
+	stderr := &saidaLimitada{limite: limiteSaidaCodigo}
+	executar := comandoIsolado(ctx, programa)
+	executar.Dir = dir
+	executar.Env = []string{}
+	executar.Stdout = stdout
+	executar.Stderr = stderr
+	err = executar.Run()
+	switch {
+	case ctx.Err() != nil:
+		return "", fmt.Errorf("tempo limite excedido ao executar o código")
+	case stdout.excedeu:
+		return "", fmt.Errorf("o programa escreveu mais de %d bytes", limiteSaidaCodigo)
+	case err != nil:
+		return "", fmt.Errorf("o programa terminou com erro (%v): %s", err, resumir(stderr.String(), tamanhoConceito))
+	}
+	return stdout.String(), nil
+}
+
+// conferirPrograma exige um package main que só importe pacotes de
+// importsPermitidos.
+func conferirPrograma(codigo string) error {
+	arquivo, err := parser.ParseFile(token.NewFileSet(), "main.go", codigo, parser.ImportsOnly)
+	if err != nil {
+		return fmt.Errorf("o código não é um programa Go válido: %v", err)
+	}
+	if arquivo.Name.Name != "main" {
+		return fmt.Errorf("o código deve ser um programa completo (package main)")
+	}
+
+	for _, imp := range arquivo.Imports {
+		caminho, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return fmt.Errorf("import inválido %s", imp.Path.Value)
+		}
+		if !contem(importsPermitidos, caminho) {
+			return fmt.Errorf("import '%s' não é permitido", caminho)
+		}
+	}
+	return nil
+}
+
+// saidaLimitada guarda até limite bytes e depois falha, o que encerra a
+// cópia da saída do programa.
+type saidaLimitada struct {
+	bytes.Buffer
+	limite  int
+	excedeu bool
+}
+
+func (s *saidaLimitada) Write(p []byte) (int, error) {
+	if s.Len()+len(p) > s.limite {
+		s.excedeu = true
+		return 0, errors.New("saída muito longa")
+	}
+	return s.Buffer.Write(p)
+}
+
+// GeradorVerificado executa o código das questões "o que este código
+// imprime?" que o gerador produz e confere a resposta com a saída real.
+// Questões conferidas saem com Verificada. Com Corrigir, uma resposta errada
+// é trocada pela saída real (ou pela opção igual a ela); sem Corrigir, ou
+// se nenhuma opção bate com a saída, a questão é descartada com erro.
+type GeradorVerificado struct {
+	GeradorQuestoes
+	Executor *ExecutorGo
+	Corrigir bool
+}
+
+func (g *GeradorVerificado) Gerar(ctx context.Context, pedido PedidoQuestao) (*Questao, error) {
+	questao, err := g.GeradorQuestoes.Gerar(ctx, pedido)
+	if err != nil {
+		return nil, err
+	}
+	if err := verificarSaida(ctx, g.Executor, questao, g.Corrigir); err != nil {
+		return nil, fmt.Errorf("questão descartada na verificação do código: %v", err)
+	}
+	return questao, nil
+}
+
+// verificarSaida executa o código de uma questão de saída de código e
+// confere, ou corrige, a resposta. Questões de outros tipos não mudam. Sem
+// isolamento de rede, a questão segue sem conferência, com Verificada falso.
+func verificarSaida(ctx context.Context, executor *ExecutorGo, questao *Questao, corrigir bool) error {
+	if questao.TipoQuestao() != TipoSaidaCodigo || strings.TrimSpace(questao.Codigo) == "" {
+		return nil
+	}
+
+	saida, err := executor.Executar(ctx, questao.Codigo)
+	if errors.Is(err, ErrSemIsolamento) {
+		questao.Verificada = false
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	obtida := strings.TrimRight(saida, "\n")
+
+	if questao.Corrigir([]string{obtida}) || normalizarSaida(questao.Resposta) == normalizarSaida(obtida) {
+		questao.Verificada = true
+		return nil
+	}
+
+	correta := ""
+	if len(questao.Opcoes) == 0 {
+		correta = obtida
+	} else {
+		for _, opcao := range questao.Opcoes {
+			if normalizarSaida(opcao) == normalizarSaida(obtida) {
+				correta = opcao
+				break
+			}
+		}
+		if correta == "" {
+			return fmt.Errorf("a saída real %q não está entre as opções", resumir(obtida, tamanhoConceito))
+		}
+	}
+	if !corrigir {
+		return fmt.Errorf("a resposta %q não é a saída real %q", questao.Resposta, resumir(obtida, tamanhoConceito))
+	}
+
+	questao.Resposta = correta
+	questao.Alternativas = nil
+	questao.Explicacao = strings.TrimSpace(questao.Explicacao +
+		"\n\nObservação: a resposta foi corrigida executando o código. A explicação acima pode se referir à resposta original, que estava errada.")
+	questao.Verificada = true
+	return nil
+}
@@ -0,0 +1,28 @@
+//go:build linux
+
+package quiz
+
+import (
+	"context"
+	"os/exec"
+	"sync"
+)
+
+// unshareDisponivel testa uma vez se o sistema permite criar um espaço de
+// usuário e de rede sem privilégios.
+var unshareDisponivel = sync.OnceValue(func() bool {
+	return exec.Command("unshare", "--user", "--map-root-user", "--net", "true").Run() == nil
+})
+
+// IsolamentoDisponivel informa se o código das questões pode ser executado
+// sem acesso à rede.
+func IsolamentoDisponivel() bool {
+	return unshareDisponivel()
+}
+
+// comandoIsolado executa programa num espaço de rede vazio. O unshare
+// substitui a si mesmo pelo programa, então o tempo limite de ctx encerra o
+// próprio programa. Só deve ser chamado se IsolamentoDisponivel.
+func comandoIsolado(ctx context.Context, programa string) *exec.Cmd {
+	return exec.CommandContext(ctx, "unshare", "--user", "--map-root-user", "--net", programa)
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package quiz
+
+import (
+	"context"
+	"os/exec"
+)
+
+// IsolamentoDisponivel informa se o código das questões pode ser executado
+// sem acesso à rede. Fora do Linux não há como garantir isso, e o código
+// não é executado.
+func IsolamentoDisponivel() bool {
+	return false
+}
+
+// comandoIsolado nunca é usado fora do Linux; veja IsolamentoDisponivel.
+func comandoIsolado(ctx context.Context, programa string) *exec.Cmd {
+	return exec.CommandContext(ctx, programa)
+}
@@ -0,0 +1,27 @@
+package quiz
+
+import "testing"
+
+func TestConferirProgramaSoAceitaImportsPermitidos(t *testing.T) {
+	programa := func(imports ...string) string {
+		codigo := "package main\n\nimport (\n"
+		for _, imp := range imports {
+			codigo += "\t\"" + imp + "\"\n"
+		}
+		return codigo + ")\n\nfunc main() {}\n"
+	}
+
+	for _, imports := range [][]string{{"fmt"}, {"fmt", "strings", "sync", "time"}, {"unicode/utf8", "math/rand"}} {
+		if err := conferirPrograma(programa(imports...)); err != nil {
+			t.Errorf("%v: %v", imports, err)
+		}
+	}
+	for _, imp := range []string{"os", "log/syslog", "net/http", "io/fs", "os/exec", "unsafe", "C", "github.com/x/y", "runtime/debug"} {
+		if err := conferirPrograma(programa("fmt", imp)); err == nil {
+			t.Errorf("o import %q deveria ser recusado", imp)
+		}
+	}
+	if err := conferirPrograma("package util\n\nfunc F() {}\n"); err == nil {
+		t.Error("um pacote que não é main deveria ser recusado")
+	}
+}
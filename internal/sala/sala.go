@@ -0,0 +1,604 @@
+// Package sala conduz partidas em grupo: um anfitrião abre a sala, os
+// jogadores entram e todos respondem à mesma questão ao mesmo tempo. Acertos
+// rápidos valem mais pontos, e o placar é mostrado depois de cada questão.
+// A conexão com os jogadores fica com quem usa o pacote; veja o pacote
+// servidor.
+package sala
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"quiz_go/internal/quiz"
+)
+
+const (
+	// TempoPadrao é o tempo para responder cada questão quando as opções
+	// não informam outro.
+	TempoPadrao = 20 * time.Second
+
+	// PausaPadrao é quanto o placar fica na tela antes da próxima questão.
+	PausaPadrao = 5 * time.Second
+
+	// PontosMaximos é quanto vale um acerto imediato. Um acerto no último
+	// instante vale a metade.
+	PontosMaximos = 1000
+
+	// tamanhoNome limita o nome dos jogadores, em caracteres.
+	tamanhoNome = 24
+
+	// folgaEventos é quantos eventos esperam por um participante lento
+	// antes de ele ser desconectado.
+	folgaEventos = 32
+
+	// toleranciaPrazo desconta o atraso da rede na chegada das respostas: a
+	// questão fica aberta por esse tempo depois do prazo anunciado.
+	toleranciaPrazo = 500 * time.Millisecond
+)
+
+var (
+	ErrNomeVazio       = errors.New("informe um nome para entrar na sala")
+	ErrNomeEmUso       = errors.New("já há um jogador com esse nome na sala")
+	ErrPartidaIniciada = errors.New("a partida já começou")
+	ErrSemJogadores    = errors.New("nenhum jogador entrou na sala")
+	ErrSemQuestao      = errors.New("nenhuma questão aguardando resposta")
+	ErrJaRespondeu     = errors.New("você já respondeu a esta questão")
+	ErrSoJogadores     = errors.New("só os jogadores respondem às questões")
+)
+
+// Opcoes escolhem as questões e os tempos de uma sala.
+type Opcoes struct {
+	// Selecao escolhe as questões como no jogo individual. Os modos que
+	// dependem do histórico de um jogador e a Prova não servem para salas.
+	Selecao quiz.Selecao
+
+	// Questoes, se informadas, são usadas na ordem, no lugar da seleção.
+	// Elas passam pela mesma validação do banco de questões.
+	Questoes []quiz.Questao
+
+	TempoQuestao time.Duration // zero usa TempoPadrao
+	Pausa        time.Duration // zero usa PausaPadrao
+}
+
+// Fase é o momento da partida.
+type Fase int
+
+const (
+	FaseEspera  Fase = iota // jogadores entrando
+	FaseQuestao             // questão aberta para respostas
+	FasePlacar              // questão corrigida, placar na tela
+	FaseFim                 // partida terminada
+)
+
+// TipoEvento identifica os eventos enviados aos participantes.
+type TipoEvento string
+
+const (
+	EventoEspera    TipoEvento = "espera"    // alguém entrou ou saiu antes do início
+	EventoQuestao   TipoEvento = "questao"   // nova questão aberta
+	EventoRespostas TipoEvento = "respostas" // mais um jogador respondeu
+	EventoCorrecao  TipoEvento = "correcao"  // questão corrigida, com o placar
+	EventoFim       TipoEvento = "fim"       // partida terminada, com o placar final
+)
+
+// Evento é o que os participantes recebem durante a partida. Os campos
+// preenchidos dependem do Tipo.
+type Evento struct {
+	Tipo TipoEvento
+
+	// Jogadores são os nomes de quem está na sala, em EventoEspera.
+	Jogadores []string
+
+	// Questao, Numero e Total vêm em EventoQuestao e EventoCorrecao. Só a
+	// correção traz a resposta e a explicação da questão.
+	Questao quiz.Questao
+	Numero  int
+	Total   int
+	Prazo   time.Time // fim do tempo da questão, em EventoQuestao
+
+	// Respondidas e Conectados contam as respostas à questão atual e os
+	// jogadores que podem responder, em EventoRespostas.
+	Respondidas int
+	Conectados  int
+
+	// Resposta é a resposta de quem recebe o evento, em EventoCorrecao; nil
+	// para quem não respondeu e para o anfitrião.
+	Resposta *Resposta
+
+	// Placar vem em EventoCorrecao e EventoFim.
+	Placar []Posicao
+}
+
+// Resposta é a resposta de um jogador a uma questão.
+type Resposta struct {
+	Escolhidas []string
+	Correta    bool
+	Pontos     int
+	Duracao    time.Duration
+}
+
+// Posicao é a linha de um jogador no placar.
+type Posicao struct {
+	Nome      string
+	Pontos    int
+	Acertos   int
+	Rodada    int // pontos ganhos na última questão
+	Conectado bool
+}
+
+// Sala é uma partida em grupo. Seus métodos podem ser chamados de várias
+// goroutines.
+type Sala struct {
+	questoes <-chan quiz.Questao
+	total    int
+	tempo    time.Duration
+	pausa    time.Duration
+	ctx      context.Context
+	cancelar context.CancelFunc
+
+	mu            sync.Mutex
+	fase          Fase
+	jogadores     map[string]*jogador
+	participantes map[*Participante]bool
+	numero        int
+	atual         quiz.Questao
+	inicioQuestao time.Time
+	prazo         time.Time
+	respondidas   int
+	todos         chan struct{} // fechado quando todos responderam à questão atual
+}
+
+type jogador struct {
+	nome         string
+	pontos       int
+	acertos      int
+	rodada       int
+	resposta     *Resposta     // resposta à questão atual
+	participante *Participante // nil enquanto desconectado
+}
+
+// Participante é a conexão de um jogador, ou do anfitrião, com a sala.
+type Participante struct {
+	sala    *Sala
+	nome    string // vazio para o anfitrião
+	eventos chan Evento
+}
+
+// Nova abre uma sala com as questões escolhidas nas opções, esperando os
+// jogadores. A geração das questões, se houver, começa em segundo plano.
+func Nova(q *quiz.Quiz, op Opcoes) (*Sala, error) {
+	s := &Sala{
+		tempo:         cmp.Or(op.TempoQuestao, TempoPadrao),
+		pausa:         cmp.Or(op.Pausa, PausaPadrao),
+		jogadores:     make(map[string]*jogador),
+		participantes: make(map[*Participante]bool),
+	}
+	s.ctx, s.cancelar = context.WithCancel(context.Background())
+
+	if len(op.Questoes) > 0 {
+		questoes, err := quiz.ValidarBanco("questões da sala", op.Questoes)
+		if err != nil {
+			s.cancelar()
+			return nil, err
+		}
+		s.questoes, s.total = canalCompleto(questoes)
+		return s, nil
+	}
+
+	if err := validarSelecao(op.Selecao); err != nil {
+		s.cancelar()
+		return nil, err
+	}
+	s.questoes, s.total = q.IniciarQuestoes(s.ctx, op.Selecao)
+	if s.total == 0 {
+		s.cancelar()
+		return nil, errors.New("nenhuma questão encontrada para esta seleção")
+	}
+	return s, nil
+}
+
+func validarSelecao(sel quiz.Selecao) error {
+	if err := sel.Validar(); err != nil {
+		return err
+	}
+	switch {
+	case sel.Modo.Pessoal():
+		return fmt.Errorf("o modo %s usa o histórico de um jogador e não pode ser usado numa sala", sel.Modo)
+	case sel.Modo == quiz.ModoProva:
+		return errors.New("a sala tem tempo por questão e não pode ser usada no modo Prova")
+	}
+	return nil
+}
+
+func canalCompleto(questoes []quiz.Questao) (<-chan quiz.Questao, int) {
+	canal := make(chan quiz.Questao, len(questoes))
+	for _, questao := range questoes {
+		canal <- questao
+	}
+	close(canal)
+	return canal, len(questoes)
+}
+
+// NovoCodigo sorteia um código de sala, fácil de ditar: seis letras e
+// números sem os que se confundem, como O e 0.
+func NovoCodigo() string {
+	const alfabeto = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	b := make([]byte, 6)
+	rand.Read(b)
+	for i := range b {
+		b[i] = alfabeto[int(b[i])%len(alfabeto)]
+	}
+	return string(b)
+}
+
+// Total retorna o número esperado de questões.
+func (s *Sala) Total() int {
+	return s.total
+}
+
+// Fase retorna o momento da partida.
+func (s *Sala) Fase() Fase {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fase
+}
+
+// Conectados conta os participantes conectados, incluindo o anfitrião.
+func (s *Sala) Conectados() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.participantes)
+}
+
+// Entrar conecta um jogador. Depois do início, só quem já estava na sala
+// pode voltar, com o mesmo nome, e recebe a questão em andamento.
+func (s *Sala) Entrar(nome string) (*Participante, error) {
+	nome = strings.TrimSpace(nome)
+	if nome == "" {
+		return nil, ErrNomeVazio
+	}
+	if utf8.RuneCountInString(nome) > tamanhoNome {
+		return nil, fmt.Errorf("o nome deve ter até %d caracteres", tamanhoNome)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, existe := s.jogadores[nome]
+	switch {
+	case existe && j.participante != nil:
+		return nil, ErrNomeEmUso
+	case !existe && s.fase != FaseEspera:
+		return nil, ErrPartidaIniciada
+	case !existe:
+		j = &jogador{nome: nome}
+		s.jogadores[nome] = j
+	}
+
+	p := s.conectar(nome)
+	j.participante = p
+	if s.fase == FaseEspera {
+		s.transmitir(s.eventoEspera())
+	} else {
+		s.retomar(p, j)
+	}
+	return p, nil
+}
+
+// Assistir conecta o anfitrião, que vê a partida sem responder.
+func (s *Sala) Assistir() *Participante {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.conectar("")
+	if s.fase == FaseEspera {
+		s.enviar(p, s.eventoEspera())
+	} else {
+		s.retomar(p, nil)
+	}
+	return p
+}
+
+func (s *Sala) conectar(nome string) *Participante {
+	p := &Participante{sala: s, nome: nome, eventos: make(chan Evento, folgaEventos)}
+	s.participantes[p] = true
+	return p
+}
+
+// retomar envia a quem reconectou o que está na tela dos outros.
+func (s *Sala) retomar(p *Participante, j *jogador) {
+	switch s.fase {
+	case FaseQuestao:
+		if j == nil || j.resposta == nil {
+			s.enviar(p, s.eventoQuestao())
+		}
+	case FasePlacar:
+		if s.numero > 0 {
+			s.enviar(p, s.eventoCorrecao(j))
+		}
+	case FaseFim:
+		s.enviar(p, Evento{Tipo: EventoFim, Placar: s.placar()})
+		s.desconectar(p)
+	}
+}
+
+// Iniciar começa a partida com os jogadores que estão na sala.
+func (s *Sala) Iniciar() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fase != FaseEspera {
+		return ErrPartidaIniciada
+	}
+	if len(s.jogadores) == 0 {
+		return ErrSemJogadores
+	}
+	s.fase = FasePlacar
+	go s.jogar()
+	return nil
+}
+
+// Encerrar termina a partida e desconecta todos.
+func (s *Sala) Encerrar() {
+	s.cancelar()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fase = FaseFim
+	for p := range s.participantes {
+		s.desconectar(p)
+	}
+}
+
+// jogar conduz a partida: abre cada questão, espera as respostas até o
+// prazo, corrige e mostra o placar.
+func (s *Sala) jogar() {
+	defer s.cancelar()
+	for {
+		var questao quiz.Questao
+		var ok bool
+		select {
+		case questao, ok = <-s.questoes:
+		case <-s.ctx.Done():
+			return
+		}
+		if !ok {
+			break
+		}
+
+		todos := s.abrirQuestao(questao)
+		prazo := time.NewTimer(s.tempo + toleranciaPrazo)
+		select {
+		case <-todos:
+		case <-prazo.C:
+		case <-s.ctx.Done():
+			prazo.Stop()
+			return
+		}
+		prazo.Stop()
+		if !s.corrigir() {
+			break
+		}
+
+		select {
+		case <-time.After(s.pausa):
+		case <-s.ctx.Done():
+			return
+		}
+	}
+	s.terminar()
+}
+
+func (s *Sala) abrirQuestao(questao quiz.Questao) <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fase = FaseQuestao
+	s.numero++
+	s.atual = questao
+	s.inicioQuestao = time.Now()
+	s.prazo = s.inicioQuestao.Add(s.tempo)
+	s.respondidas = 0
+	s.todos = make(chan struct{})
+	for _, j := range s.jogadores {
+		j.resposta = nil
+	}
+	s.transmitir(s.eventoQuestao())
+	return s.todos
+}
+
+// corrigir soma os pontos da questão atual e envia a correção a cada um.
+// Retorna falso quando era a última questão.
+func (s *Sala) corrigir() (continuar bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fase = FasePlacar
+	for _, j := range s.jogadores {
+		j.rodada = 0
+		if j.resposta != nil && j.resposta.Correta {
+			j.rodada = j.resposta.Pontos
+			j.pontos += j.resposta.Pontos
+			j.acertos++
+		}
+	}
+	for p := range s.participantes {
+		s.enviar(p, s.eventoCorrecao(s.jogadores[p.nome]))
+	}
+	return s.numero < s.total
+}
+
+func (s *Sala) terminar() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fase = FaseFim
+	s.transmitir(Evento{Tipo: EventoFim, Placar: s.placar()})
+	for p := range s.participantes {
+		s.desconectar(p)
+	}
+}
+
+// Eventos retorna o canal de eventos da partida, fechado quando ela
+// termina ou o participante é desconectado.
+func (p *Participante) Eventos() <-chan Evento {
+	return p.eventos
+}
+
+// Nome retorna o nome do jogador, ou vazio para o anfitrião.
+func (p *Participante) Nome() string {
+	return p.nome
+}
+
+// Responder responde à questão aberta com as opções marcadas ou o texto
+// digitado. Só a primeira resposta de cada jogador conta.
+func (p *Participante) Responder(escolhidas []string) error {
+	s := p.sala
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p.nome == "" {
+		return ErrSoJogadores
+	}
+	duracao := time.Since(s.inicioQuestao)
+	if s.fase != FaseQuestao || duracao > s.tempo+toleranciaPrazo {
+		return ErrSemQuestao
+	}
+	j := s.jogadores[p.nome]
+	if j.resposta != nil {
+		return ErrJaRespondeu
+	}
+
+	j.resposta = &Resposta{Escolhidas: escolhidas, Correta: s.atual.Corrigir(escolhidas), Duracao: duracao}
+	if j.resposta.Correta {
+		j.resposta.Pontos = pontuar(duracao, s.tempo)
+	}
+	s.respondidas++
+	s.transmitir(Evento{Tipo: EventoRespostas, Respondidas: s.respondidas, Conectados: s.jogadoresConectados()})
+	s.verificarTodos()
+	return nil
+}
+
+// Sair desconecta o participante. Antes do início, o jogador sai da sala;
+// depois, continua no placar e pode voltar com o mesmo nome.
+func (p *Participante) Sair() {
+	s := p.sala
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.participantes[p] {
+		return
+	}
+	s.desconectar(p)
+	if p.nome == "" {
+		return
+	}
+	if s.fase == FaseEspera {
+		delete(s.jogadores, p.nome)
+		s.transmitir(s.eventoEspera())
+	}
+	s.verificarTodos()
+}
+
+// pontuar dá PontosMaximos a um acerto imediato e metade disso a um acerto
+// no fim do prazo.
+func pontuar(duracao, tempo time.Duration) int {
+	fracao := min(1, float64(duracao)/float64(tempo))
+	return int(math.Round(PontosMaximos * (1 - fracao/2)))
+}
+
+// verificarTodos fecha a questão atual quando todos os jogadores conectados
+// já responderam.
+func (s *Sala) verificarTodos() {
+	if s.fase == FaseQuestao && s.respondidas >= s.jogadoresConectados() {
+		select {
+		case <-s.todos:
+		default:
+			close(s.todos)
+		}
+	}
+}
+
+// jogadoresConectados conta os jogadores conectados que podem responder à
+// questão atual, incluindo quem já respondeu.
+func (s *Sala) jogadoresConectados() int {
+	n := 0
+	for _, j := range s.jogadores {
+		if j.participante != nil || j.resposta != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// enviar entrega o evento sem esperar. Quem não acompanha os eventos é
+// desconectado, para não segurar a partida.
+func (s *Sala) enviar(p *Participante, ev Evento) {
+	select {
+	case p.eventos <- ev:
+	default:
+		s.desconectar(p)
+	}
+}
+
+func (s *Sala) transmitir(ev Evento) {
+	for p := range s.participantes {
+		s.enviar(p, ev)
+	}
+}
+
+func (s *Sala) desconectar(p *Participante) {
+	if !s.participantes[p] {
+		return
+	}
+	delete(s.participantes, p)
+	close(p.eventos)
+	if j, ok := s.jogadores[p.nome]; ok && j.participante == p {
+		j.participante = nil
+	}
+}
+
+func (s *Sala) eventoEspera() Evento {
+	nomes := make([]string, 0, len(s.jogadores))
+	for nome := range s.jogadores {
+		nomes = append(nomes, nome)
+	}
+	slices.Sort(nomes)
+	return Evento{Tipo: EventoEspera, Jogadores: nomes}
+}
+
+func (s *Sala) eventoQuestao() Evento {
+	return Evento{Tipo: EventoQuestao, Questao: s.atual, Numero: s.numero, Total: s.total, Prazo: s.prazo}
+}
+
+func (s *Sala) eventoCorrecao(j *jogador) Evento {
+	ev := Evento{Tipo: EventoCorrecao, Questao: s.atual, Numero: s.numero, Total: s.total, Placar: s.placar()}
+	if j != nil {
+		ev.Resposta = j.resposta
+	}
+	return ev
+}
+
+// placar ordena os jogadores por pontos, depois por acertos e por nome.
+func (s *Sala) placar() []Posicao {
+	placar := make([]Posicao, 0, len(s.jogadores))
+	for _, j := range s.jogadores {
+		placar = append(placar, Posicao{
+			Nome:      j.nome,
+			Pontos:    j.pontos,
+			Acertos:   j.acertos,
+			Rodada:    j.rodada,
+			Conectado: j.participante != nil,
+		})
+	}
+	slices.SortFunc(placar, func(a, b Posicao) int {
+		return cmp.Or(
+			cmp.Compare(b.Pontos, a.Pontos),
+			cmp.Compare(b.Acertos, a.Acertos),
+			strings.Compare(a.Nome, b.Nome),
+		)
+	})
+	return placar
+}
@@ -0,0 +1,248 @@
+package sala
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"quiz_go/internal/config"
+	"quiz_go/internal/quiz"
+)
+
+func questoesTeste(n int) []quiz.Questao {
+	questoes := make([]quiz.Questao, n)
+	for i := range questoes {
+		questoes[i] = quiz.Questao{
+			ID:          i + 1,
+			Questao:     fmt.Sprintf("Questão %d", i+1),
+			Opcoes:      []string{"a", "b", "c", "d"},
+			Resposta:    "a",
+			Explicacao:  "Porque sim.",
+			Dificuldade: "facil",
+			Categoria:   "sintaxe",
+		}
+	}
+	return questoes
+}
+
+// novaSalaTeste abre uma sala com n questoesTeste e pausa curta.
+func novaSalaTeste(t *testing.T, n int, tempo time.Duration) *Sala {
+	t.Helper()
+	s, err := Nova(nil, Opcoes{Questoes: questoesTeste(n), TempoQuestao: tempo, Pausa: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Encerrar)
+	return s
+}
+
+// esperar lê eventos do participante até um do tipo pedido.
+func esperar(t *testing.T, p *Participante, tipo TipoEvento) Evento {
+	t.Helper()
+	limite := time.After(5 * time.Second)
+	for {
+		select {
+		case ev, ok := <-p.Eventos():
+			if !ok {
+				t.Fatalf("%q: eventos encerrados esperando %s", p.Nome(), tipo)
+			}
+			if ev.Tipo == tipo {
+				return ev
+			}
+		case <-limite:
+			t.Fatalf("%q: tempo esgotado esperando %s", p.Nome(), tipo)
+		}
+	}
+}
+
+func TestPartida(t *testing.T) {
+	s := novaSalaTeste(t, 2, 300*time.Millisecond)
+
+	anfitriao := s.Assistir()
+	ana, err := s.Entrar("Ana")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bia, err := s.Entrar(" Bia ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// O anfitrião vê a sala vazia e depois cada jogador que entra.
+	for _, esperados := range []string{"[]", "[Ana]", "[Ana Bia]"} {
+		if ev := esperar(t, anfitriao, EventoEspera); fmt.Sprint(ev.Jogadores) != esperados {
+			t.Errorf("jogadores = %v, esperado %s", ev.Jogadores, esperados)
+		}
+	}
+
+	if err := s.Iniciar(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Questão 1: as duas respondem, e a correção vem sem esperar o prazo.
+	q1 := esperar(t, ana, EventoQuestao)
+	if esperar(t, bia, EventoQuestao).Questao.ID != q1.Questao.ID || q1.Numero != 1 || q1.Total != 2 {
+		t.Fatalf("questão 1 = %+v", q1)
+	}
+	if err := ana.Responder([]string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ana.Responder([]string{"b"}); !errors.Is(err, ErrJaRespondeu) {
+		t.Errorf("segunda resposta: %v", err)
+	}
+	if err := anfitriao.Responder([]string{"a"}); !errors.Is(err, ErrSoJogadores) {
+		t.Errorf("resposta do anfitrião: %v", err)
+	}
+	if err := bia.Responder([]string{"b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := esperar(t, ana, EventoCorrecao)
+	if c.Resposta == nil || !c.Resposta.Correta || c.Resposta.Pontos <= PontosMaximos/2 {
+		t.Errorf("correção de Ana = %+v", c.Resposta)
+	}
+	if c.Questao.Resposta != "a" || c.Questao.Explicacao == "" {
+		t.Errorf("a correção deveria trazer a resposta e a explicação: %+v", c.Questao)
+	}
+	if len(c.Placar) != 2 || c.Placar[0].Nome != "Ana" || c.Placar[1].Pontos != 0 {
+		t.Errorf("placar = %+v", c.Placar)
+	}
+	if c := esperar(t, bia, EventoCorrecao); c.Resposta == nil || c.Resposta.Correta {
+		t.Errorf("correção de Bia = %+v", c.Resposta)
+	}
+
+	// Questão 2: só Bia responde, e a questão fecha no prazo.
+	esperar(t, ana, EventoQuestao)
+	esperar(t, bia, EventoQuestao)
+	if err := bia.Responder([]string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	if c := esperar(t, ana, EventoCorrecao); c.Resposta != nil {
+		t.Errorf("Ana não respondeu, mas veio %+v", c.Resposta)
+	}
+
+	fim := esperar(t, anfitriao, EventoFim)
+	if len(fim.Placar) != 2 || fim.Placar[0].Acertos != 1 || fim.Placar[1].Acertos != 1 {
+		t.Errorf("placar final = %+v", fim.Placar)
+	}
+	if _, ok := <-anfitriao.Eventos(); ok {
+		t.Error("os eventos deveriam ser encerrados no fim da partida")
+	}
+	if s.Fase() != FaseFim {
+		t.Errorf("fase = %v, esperado FaseFim", s.Fase())
+	}
+}
+
+func TestRespostaLogoDepoisDoPrazo(t *testing.T) {
+	const tempo = 100 * time.Millisecond
+	s := novaSalaTeste(t, 2, tempo)
+	ana, err := s.Entrar("Ana")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Iniciar(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Atrasada pela rede, dentro da tolerância: ainda conta, com os pontos
+	// do fim do prazo.
+	q := esperar(t, ana, EventoQuestao)
+	if restante := time.Until(q.Prazo); restante > tempo {
+		t.Errorf("prazo anunciado em %v, esperado até %v: a tolerância não é anunciada", restante, tempo)
+	}
+	time.Sleep(tempo + toleranciaPrazo/3)
+	if err := ana.Responder([]string{"a"}); err != nil {
+		t.Fatalf("resposta dentro da tolerância: %v", err)
+	}
+	if c := esperar(t, ana, EventoCorrecao); c.Resposta == nil || c.Resposta.Pontos != PontosMaximos/2 {
+		t.Errorf("correção = %+v", c.Resposta)
+	}
+
+	// Depois da tolerância, a questão já foi corrigida.
+	esperar(t, ana, EventoQuestao)
+	time.Sleep(tempo + toleranciaPrazo + 100*time.Millisecond)
+	if err := ana.Responder([]string{"a"}); !errors.Is(err, ErrSemQuestao) {
+		t.Errorf("resposta depois da tolerância: %v, esperado ErrSemQuestao", err)
+	}
+}
+
+func TestEntrar(t *testing.T) {
+	s := novaSalaTeste(t, 1, time.Minute)
+
+	if _, err := s.Entrar("  "); !errors.Is(err, ErrNomeVazio) {
+		t.Errorf("nome vazio: %v", err)
+	}
+	ana, _ := s.Entrar("Ana")
+	if _, err := s.Entrar("Ana"); !errors.Is(err, ErrNomeEmUso) {
+		t.Errorf("nome repetido: %v", err)
+	}
+
+	// Quem sai antes do início deixa a sala.
+	ana.Sair()
+	if err := s.Iniciar(); !errors.Is(err, ErrSemJogadores) {
+		t.Errorf("iniciar sem jogadores: %v", err)
+	}
+
+	ana, _ = s.Entrar("Ana")
+	if err := s.Iniciar(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Entrar("Bia"); !errors.Is(err, ErrPartidaIniciada) {
+		t.Errorf("entrar depois do início: %v", err)
+	}
+
+	// Quem sai depois do início pode voltar e recebe a questão aberta.
+	questao := esperar(t, ana, EventoQuestao)
+	ana.Sair()
+	deVolta, err := s.Entrar("Ana")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev := esperar(t, deVolta, EventoQuestao); ev.Questao.ID != questao.Questao.ID {
+		t.Errorf("questão retomada = %d, esperado %d", ev.Questao.ID, questao.Questao.ID)
+	}
+}
+
+func TestNovaValidaAsQuestoes(t *testing.T) {
+	cfg := config.Padrao()
+	cfg.Gerador = quiz.GeradorNomeEstatico
+	cfg.ArquivoStats = filepath.Join(t.TempDir(), "stats.json")
+	cfg.ArquivoGeradas = ""
+	q := quiz.NewQuiz(cfg)
+
+	for _, modo := range []quiz.Modo{quiz.ModoRevisao, quiz.ModoAdaptativo, quiz.ModoProva} {
+		if _, err := Nova(q, Opcoes{Selecao: quiz.Selecao{Modo: modo}}); err == nil {
+			t.Errorf("o modo %s não deveria ser aceito numa sala", modo)
+		}
+	}
+
+	invalida := questoesTeste(1)
+	invalida[0].Resposta = "z"
+	if _, err := Nova(q, Opcoes{Questoes: invalida}); err == nil {
+		t.Error("uma questão com resposta fora das opções deveria ser recusada")
+	}
+
+	s, err := Nova(q, Opcoes{Selecao: quiz.Selecao{Modo: quiz.ModoRapido, Quantidade: 3}})
+	if err != nil || s.Total() != 3 {
+		t.Fatalf("sala do banco: %v", err)
+	}
+	s.Encerrar()
+}
+
+func TestPontuar(t *testing.T) {
+	casos := []struct {
+		duracao time.Duration
+		pontos  int
+	}{
+		{0, PontosMaximos},
+		{10 * time.Second, 750},
+		{20 * time.Second, 500},
+		{25 * time.Second, 500},
+	}
+	for _, c := range casos {
+		if p := pontuar(c.duracao, 20*time.Second); p != c.pontos {
+			t.Errorf("pontuar(%v) = %d, esperado %d", c.duracao, p, c.pontos)
+		}
+	}
+}
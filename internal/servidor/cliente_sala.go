@@ -0,0 +1,139 @@
+package servidor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+
+	"quiz_go/internal/sala"
+)
+
+// ClienteSala é a conexão de um jogador ou do anfitrião com uma sala aberta
+// num servidor do quiz, como o do comando serve.
+type ClienteSala struct {
+	conn *websocket.Conn
+}
+
+// ErroSala é um pedido recusado pela sala, como uma resposta repetida. A
+// conexão continua aberta.
+type ErroSala struct {
+	Mensagem string
+}
+
+func (e *ErroSala) Error() string {
+	return e.Mensagem
+}
+
+// CriarSala abre uma sala no servidor em base, como http://localhost:7070,
+// e retorna o código para os jogadores e a chave do anfitrião.
+func CriarSala(ctx context.Context, base string, op sala.Opcoes) (codigo, chave string, err error) {
+	pedido := pedidoSala{
+		pedidoSessao: pedidoSessao{
+			Modo:        string(op.Selecao.Modo),
+			Dificuldade: op.Selecao.Dificuldade,
+			Categoria:   op.Selecao.Categoria,
+			Tipo:        op.Selecao.Tipo,
+			Quantidade:  op.Selecao.Quantidade,
+		},
+		Questoes:         op.Questoes,
+		TempoQuestaoSegs: op.TempoQuestao.Seconds(),
+		PausaSegs:        op.Pausa.Seconds(),
+	}
+	dados, err := json.Marshal(pedido)
+	if err != nil {
+		return "", "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(base, "/")+"/api/salas", bytes.NewReader(dados))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return "", "", erroResposta(resp)
+	}
+
+	var criada salaCriada
+	if err := json.NewDecoder(resp.Body).Decode(&criada); err != nil {
+		return "", "", fmt.Errorf("resposta inválida do servidor: %v", err)
+	}
+	return criada.Codigo, criada.Chave, nil
+}
+
+// EntrarSala conecta um jogador à sala com o código informado.
+func EntrarSala(ctx context.Context, base, codigo, nome string) (*ClienteSala, error) {
+	return conectarCliente(ctx, base, codigo, url.Values{"nome": {nome}})
+}
+
+// AssistirSala conecta o anfitrião à sala, com a chave de CriarSala.
+func AssistirSala(ctx context.Context, base, codigo, chave string) (*ClienteSala, error) {
+	return conectarCliente(ctx, base, codigo, url.Values{"chave": {chave}})
+}
+
+func conectarCliente(ctx context.Context, base, codigo string, params url.Values) (*ClienteSala, error) {
+	endereco := strings.TrimSuffix(base, "/") + "/api/salas/" + url.PathEscape(strings.ToUpper(codigo)) + "/ws?" + params.Encode()
+	conn, resp, err := websocket.Dial(ctx, endereco, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode != http.StatusSwitchingProtocols {
+			return nil, erroResposta(resp)
+		}
+		return nil, err
+	}
+	return &ClienteSala{conn: conn}, nil
+}
+
+// Proximo espera o próximo evento da partida. Retorna io.EOF quando ela
+// termina e *ErroSala quando um pedido foi recusado.
+func (c *ClienteSala) Proximo(ctx context.Context) (sala.Evento, error) {
+	var e eventoSala
+	if err := wsjson.Read(ctx, c.conn, &e); err != nil {
+		if websocket.CloseStatus(err) == websocket.StatusNormalClosure {
+			return sala.Evento{}, io.EOF
+		}
+		return sala.Evento{}, err
+	}
+	if e.Tipo == "erro" {
+		return sala.Evento{}, &ErroSala{Mensagem: e.Erro}
+	}
+	return e.evento(), nil
+}
+
+// Iniciar começa a partida. Só o anfitrião pode iniciá-la.
+func (c *ClienteSala) Iniciar(ctx context.Context) error {
+	return wsjson.Write(ctx, c.conn, mensagemSala{Tipo: "iniciar"})
+}
+
+// Responder envia a resposta à questão aberta: as opções marcadas ou o
+// texto digitado.
+func (c *ClienteSala) Responder(ctx context.Context, escolhidas []string) error {
+	return wsjson.Write(ctx, c.conn, mensagemSala{Tipo: "responder", pedidoResposta: pedidoResposta{Escolhidas: escolhidas}})
+}
+
+// Fechar sai da sala.
+func (c *ClienteSala) Fechar() {
+	c.conn.Close(websocket.StatusNormalClosure, "")
+}
+
+// erroResposta lê a mensagem de erro da API na resposta.
+func erroResposta(resp *http.Response) error {
+	var corpo struct {
+		Erro string `json:"erro"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1024)).Decode(&corpo); err != nil || corpo.Erro == "" {
+		return errors.New(resp.Status)
+	}
+	return errors.New(corpo.Erro)
+}
@@ -0,0 +1,446 @@
+package servidor
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"quiz_go/internal/quiz"
+	"quiz_go/internal/sala"
+	"quiz_go/internal/stats"
+)
+
+// pedidoSessao é o corpo de POST /api/sessoes. Campos vazios usam o padrão
+// do modo; o modo vazio é "all".
+type pedidoSessao struct {
+	Modo        string `json:"modo"`
+	Dificuldade string `json:"dificuldade"`
+	Categoria   string `json:"categoria"`
+	Tipo        string `json:"tipo"`
+	Quantidade  int    `json:"quantidade"`
+}
+
+func (p pedidoSessao) selecao() (quiz.Selecao, error) {
+	categoria, err := quiz.ValidarCategoria(strings.TrimSpace(p.Categoria))
+	if err != nil {
+		return quiz.Selecao{}, err
+	}
+	sel := quiz.Selecao{
+		Modo:        quiz.Modo(strings.TrimSpace(p.Modo)),
+		Dificuldade: strings.TrimSpace(p.Dificuldade),
+		Categoria:   categoria,
+		Tipo:        strings.TrimSpace(p.Tipo),
+		Quantidade:  p.Quantidade,
+	}
+	if sel.Modo == "" {
+		sel.Modo = quiz.ModoTodas
+	}
+	return sel, sel.Validar()
+}
+
+type modoJSON struct {
+	Modo    quiz.Modo `json:"modo"`
+	Nome    string    `json:"nome"`
+	EmGrupo bool      `json:"em_grupo"` // serve para as salas
+}
+
+type sessaoCriada struct {
+	ID               string     `json:"id"`
+	Total            int        `json:"total"`
+	DuracaoProvaSegs float64    `json:"duracao_prova_s,omitempty"`
+	FimProva         *time.Time `json:"fim_prova,omitempty"`
+}
+
+// pedidoResposta é o corpo de POST /api/sessoes/{id}/resposta: as opções
+// marcadas em escolhidas ou o texto digitado em resposta. Com esgotado, o
+// cliente avisa que o prazo da questão acabou sem resposta.
+type pedidoResposta struct {
+	Escolhidas []string `json:"escolhidas"`
+	Resposta   string   `json:"resposta"`
+	Esgotado   bool     `json:"esgotado"`
+}
+
+func (p pedidoResposta) escolhidas() []string {
+	if len(p.Escolhidas) == 0 && p.Resposta != "" {
+		return []string{p.Resposta}
+	}
+	return p.Escolhidas
+}
+
+// questaoPublica é a questão como o cliente a vê, sem a resposta nem a
+// explicação.
+type questaoPublica struct {
+	Numero           int        `json:"numero"`
+	Total            int        `json:"total"`
+	ID               int        `json:"id"`
+	Tipo             string     `json:"tipo"`
+	Questao          string     `json:"questao"`
+	Codigo           string     `json:"codigo,omitempty"`
+	Opcoes           []string   `json:"opcoes,omitempty"`
+	RespostaDigitada bool       `json:"resposta_digitada"`
+	Dificuldade      string     `json:"dificuldade"`
+	Categoria        string     `json:"categoria"`
+	Prazo            *time.Time `json:"prazo,omitempty"`
+}
+
+// proximaQuestao é a resposta de GET /api/sessoes/{id}/questao: a questão
+// atual ou, com fim, o aviso de que o quiz terminou.
+type proximaQuestao struct {
+	Fim bool `json:"fim"`
+	*questaoPublica
+}
+
+func publicar(s *quiz.Sessao, questao quiz.Questao) *questaoPublica {
+	return publicarQuestao(questao, s.Numero(), s.Total(), s.Prazo())
+}
+
+func publicarQuestao(questao quiz.Questao, numero, total int, prazo time.Time) *questaoPublica {
+	return &questaoPublica{
+		Numero:           numero,
+		Total:            total,
+		ID:               questao.ID,
+		Tipo:             questao.TipoQuestao(),
+		Questao:          questao.Questao,
+		Codigo:           questao.Codigo,
+		Opcoes:           questao.OpcoesExibidas(),
+		RespostaDigitada: questao.RespostaDigitada(),
+		Dificuldade:      questao.Dificuldade,
+		Categoria:        questao.Categoria,
+		Prazo:            horario(prazo),
+	}
+}
+
+// questao refaz a questão recebida por um cliente, sem a resposta.
+func (p *questaoPublica) questao() quiz.Questao {
+	questao := quiz.Questao{
+		ID:          p.ID,
+		Tipo:        p.Tipo,
+		Questao:     p.Questao,
+		Codigo:      p.Codigo,
+		Opcoes:      p.Opcoes,
+		Dificuldade: p.Dificuldade,
+		Categoria:   p.Categoria,
+	}
+	if p.Tipo == quiz.TipoVerdadeiroFalso {
+		questao.Opcoes = nil
+	}
+	return questao
+}
+
+type correcaoJSON struct {
+	Numero          int    `json:"numero"`
+	Correta         bool   `json:"correta"`
+	TempoEsgotado   bool   `json:"tempo_esgotado"`
+	RespostaCorreta string `json:"resposta_correta"`
+	// OpcoesCorretas são as opções certas, para destacá-las; vazio quando a
+	// resposta é digitada.
+	OpcoesCorretas []string `json:"opcoes_corretas,omitempty"`
+	Explicacao     string   `json:"explicacao"`
+	DuracaoSegs    float64  `json:"duracao_s"`
+	Acertos        int      `json:"acertos"`
+	Restantes      int      `json:"restantes"`
+	Fim            bool     `json:"fim"`
+}
+
+func correcao(r quiz.Resultado, s *quiz.Sessao, fim bool) correcaoJSON {
+	return correcaoJSON{
+		Numero:          r.Numero,
+		Correta:         r.Correta,
+		TempoEsgotado:   r.Esgotado,
+		RespostaCorreta: r.Questao.RespostaEsperada(),
+		OpcoesCorretas:  opcoesCorretas(r.Questao),
+		Explicacao:      r.Questao.Explicacao,
+		DuracaoSegs:     r.Duracao.Seconds(),
+		Acertos:         r.Acertos,
+		Restantes:       s.Restantes(),
+		Fim:             fim,
+	}
+}
+
+// opcoesCorretas retorna as opções certas da questão, ou nil quando a
+// resposta é digitada.
+func opcoesCorretas(questao quiz.Questao) []string {
+	switch {
+	case questao.RespostaDigitada():
+		return nil
+	case questao.TipoQuestao() == quiz.TipoMultiplasRespostas:
+		return questao.Respostas
+	default:
+		return []string{questao.Resposta}
+	}
+}
+
+type respostaResumo struct {
+	QuestaoID     int     `json:"questao_id"`
+	Categoria     string  `json:"categoria"`
+	Dificuldade   string  `json:"dificuldade"`
+	Escolhida     string  `json:"escolhida"`
+	Correta       bool    `json:"correta"`
+	TempoEsgotado bool    `json:"tempo_esgotado"`
+	DuracaoSegs   float64 `json:"duracao_s"`
+}
+
+type resumoJSON struct {
+	Acertos            int              `json:"acertos"`
+	Respondidas        int              `json:"respondidas"`
+	SemResposta        int              `json:"sem_resposta"` // fim da prova; incluídas em respondidas
+	Percentual         float64          `json:"percentual"`
+	SemTempo           int              `json:"sem_tempo"`
+	Pontos             *float64         `json:"pontos,omitempty"` // só com bônus de velocidade
+	TempoSegs          float64          `json:"tempo_s"`
+	HabilidadeAnterior float64          `json:"habilidade_anterior"`
+	Habilidade         float64          `json:"habilidade"`
+	Nivel              string           `json:"nivel"`
+	Quadro             string           `json:"quadro"` // nome do quadro de recordes
+	Recorde            bool             `json:"recorde"`
+	RecordePessoal     bool             `json:"recorde_pessoal"`
+	ErroEstatisticas   string           `json:"erro_estatisticas,omitempty"`
+	Respostas          []respostaResumo `json:"respostas"`
+}
+
+func publicarResumo(r quiz.Resumo) resumoJSON {
+	resumo := resumoJSON{
+		Acertos:            r.Acertos,
+		Respondidas:        r.Respondidas,
+		SemResposta:        r.SemResposta,
+		Percentual:         r.Percentual(),
+		SemTempo:           r.SemTempo(),
+		TempoSegs:          r.Tempo.Seconds(),
+		HabilidadeAnterior: r.HabilidadeAnterior,
+		Habilidade:         r.Habilidade,
+		Nivel:              stats.NivelHabilidade(r.Habilidade),
+		Quadro:             quiz.Quadro{Modo: r.Modo, Dificuldade: r.Dificuldade}.Nome(),
+		Recorde:            r.Recorde,
+		RecordePessoal:     r.RecordePessoal,
+		Respostas:          make([]respostaResumo, len(r.Respostas)),
+	}
+	if r.Bonus {
+		resumo.Pontos = &r.Pontos
+	}
+	if r.ErroEstatisticas != nil {
+		resumo.ErroEstatisticas = r.ErroEstatisticas.Error()
+	}
+	for i, resposta := range r.Respostas {
+		resumo.Respostas[i] = respostaResumo{
+			QuestaoID:     resposta.QuestaoID,
+			Categoria:     resposta.Categoria,
+			Dificuldade:   resposta.Dificuldade,
+			Escolhida:     resposta.Escolhida,
+			Correta:       resposta.Correta,
+			TempoEsgotado: resposta.TempoEsgotado,
+			DuracaoSegs:   resposta.Duracao().Seconds(),
+		}
+	}
+	return resumo
+}
+
+type desempenhoJSON struct {
+	Nome           string  `json:"nome"`
+	Respondidas    int     `json:"respondidas"`
+	Acertos        int     `json:"acertos"`
+	SemTempo       int     `json:"sem_tempo"`
+	Percentual     float64 `json:"percentual"`
+	TempoMedioSegs float64 `json:"tempo_medio_s"`
+}
+
+type semanaJSON struct {
+	Inicio string `json:"inicio"` // segunda-feira da semana, como 2006-01-02
+	desempenhoJSON
+}
+
+type estatisticasJSON struct {
+	TotalQuizzes    int              `json:"total_quizzes"`
+	TotalAcertos    int              `json:"total_acertos"`
+	TotalQuestoes   int              `json:"total_questoes"`
+	MelhorScore     int              `json:"melhor_score"`
+	MediaPercentual float64          `json:"media_percentual"`
+	UltimoQuiz      string           `json:"ultimo_quiz,omitempty"`
+	Habilidade      float64          `json:"habilidade"`
+	Nivel           string           `json:"nivel"`
+	PorCategoria    []desempenhoJSON `json:"por_categoria"`
+	PorDificuldade  []desempenhoJSON `json:"por_dificuldade"`
+	Tendencia       []semanaJSON     `json:"tendencia"`
+}
+
+func publicarEstatisticas(e stats.Estatisticas) estatisticasJSON {
+	resultado := estatisticasJSON{
+		TotalQuizzes:    e.TotalQuizzes,
+		TotalAcertos:    e.TotalAcertos,
+		TotalQuestoes:   e.TotalQuestoes,
+		MelhorScore:     e.MelhorScore,
+		MediaPercentual: e.MediaPercentual,
+		UltimoQuiz:      e.UltimoQuiz,
+		Habilidade:      e.HabilidadeAtual(),
+		Nivel:           stats.NivelHabilidade(e.HabilidadeAtual()),
+		PorCategoria:    publicarDesempenhos(e.PorCategoria()),
+		PorDificuldade:  publicarDesempenhos(e.PorDificuldade()),
+		Tendencia:       []semanaJSON{},
+	}
+	for _, p := range e.Tendencia(8) {
+		resultado.Tendencia = append(resultado.Tendencia, semanaJSON{
+			Inicio:         p.Inicio.Format("2006-01-02"),
+			desempenhoJSON: publicarDesempenho(p.Desempenho),
+		})
+	}
+	return resultado
+}
+
+func publicarDesempenhos(desempenhos []stats.Desempenho) []desempenhoJSON {
+	resultado := make([]desempenhoJSON, len(desempenhos))
+	for i, d := range desempenhos {
+		resultado[i] = publicarDesempenho(d)
+	}
+	return resultado
+}
+
+func publicarDesempenho(d stats.Desempenho) desempenhoJSON {
+	return desempenhoJSON{
+		Nome:           d.Nome,
+		Respondidas:    d.Respondidas,
+		Acertos:        d.Acertos,
+		SemTempo:       d.SemTempo,
+		Percentual:     d.Percentual(),
+		TempoMedioSegs: d.TempoMedio().Seconds(),
+	}
+}
+
+// pedidoSala é o corpo de POST /api/salas: a seleção, como em pedidoSessao,
+// ou as questões da partida, e os tempos em segundos. Tempos zerados usam o
+// padrão da sala.
+type pedidoSala struct {
+	pedidoSessao
+	Questoes         []quiz.Questao `json:"questoes"`
+	TempoQuestaoSegs float64        `json:"tempo_questao_s"`
+	PausaSegs        float64        `json:"pausa_s"`
+}
+
+func (p pedidoSala) opcoes() (sala.Opcoes, error) {
+	if p.TempoQuestaoSegs < 0 || p.PausaSegs < 0 {
+		return sala.Opcoes{}, errors.New("os tempos da sala não podem ser negativos")
+	}
+	op := sala.Opcoes{
+		Questoes:     p.Questoes,
+		TempoQuestao: time.Duration(p.TempoQuestaoSegs * float64(time.Second)),
+		Pausa:        time.Duration(p.PausaSegs * float64(time.Second)),
+	}
+	if len(p.Questoes) == 0 {
+		sel, err := p.selecao()
+		if err != nil {
+			return sala.Opcoes{}, err
+		}
+		op.Selecao = sel
+	}
+	return op, nil
+}
+
+type salaCriada struct {
+	Codigo string `json:"codigo"`
+	Chave  string `json:"chave"` // identifica o anfitrião ao conectar
+	Total  int    `json:"total"`
+}
+
+// mensagemSala é o que os clientes enviam pelo WebSocket da sala: "iniciar",
+// só do anfitrião, ou "responder", com a resposta como em pedidoResposta.
+type mensagemSala struct {
+	Tipo string `json:"tipo"`
+	pedidoResposta
+}
+
+// eventoSala é um sala.Evento como os clientes o recebem, ou um pedido
+// recusado, com o tipo "erro".
+type eventoSala struct {
+	Tipo        string          `json:"tipo"`
+	Erro        string          `json:"erro,omitempty"`
+	Jogadores   []string        `json:"jogadores,omitempty"`
+	Questao     *questaoPublica `json:"questao,omitempty"`
+	Respondidas int             `json:"respondidas,omitempty"`
+	Conectados  int             `json:"conectados,omitempty"`
+	Correcao    *correcaoSala   `json:"correcao,omitempty"`
+	Placar      []posicaoJSON   `json:"placar,omitempty"`
+}
+
+// correcaoSala traz a resposta da questão e, se quem recebe respondeu, a
+// correção e os pontos dele.
+type correcaoSala struct {
+	RespostaCorreta string   `json:"resposta_correta"`
+	OpcoesCorretas  []string `json:"opcoes_corretas,omitempty"`
+	Explicacao      string   `json:"explicacao"`
+	Respondeu       bool     `json:"respondeu"`
+	Correta         bool     `json:"correta"`
+	Pontos          int      `json:"pontos"`
+	DuracaoSegs     float64  `json:"duracao_s,omitempty"`
+}
+
+type posicaoJSON struct {
+	Nome      string `json:"nome"`
+	Pontos    int    `json:"pontos"`
+	Acertos   int    `json:"acertos"`
+	Rodada    int    `json:"rodada"` // pontos da última questão
+	Conectado bool   `json:"conectado"`
+}
+
+func publicarEvento(ev sala.Evento) eventoSala {
+	e := eventoSala{
+		Tipo:        string(ev.Tipo),
+		Jogadores:   ev.Jogadores,
+		Respondidas: ev.Respondidas,
+		Conectados:  ev.Conectados,
+	}
+	switch ev.Tipo {
+	case sala.EventoQuestao:
+		e.Questao = publicarQuestao(ev.Questao, ev.Numero, ev.Total, ev.Prazo)
+	case sala.EventoCorrecao:
+		e.Questao = publicarQuestao(ev.Questao, ev.Numero, ev.Total, time.Time{})
+		e.Correcao = &correcaoSala{
+			RespostaCorreta: ev.Questao.RespostaEsperada(),
+			OpcoesCorretas:  opcoesCorretas(ev.Questao),
+			Explicacao:      ev.Questao.Explicacao,
+		}
+		if r := ev.Resposta; r != nil {
+			e.Correcao.Respondeu = true
+			e.Correcao.Correta = r.Correta
+			e.Correcao.Pontos = r.Pontos
+			e.Correcao.DuracaoSegs = r.Duracao.Seconds()
+		}
+	}
+	for _, p := range ev.Placar {
+		e.Placar = append(e.Placar, posicaoJSON(p))
+	}
+	return e
+}
+
+// evento refaz, no cliente, o sala.Evento publicado pelo servidor.
+func (e eventoSala) evento() sala.Evento {
+	ev := sala.Evento{
+		Tipo:        sala.TipoEvento(e.Tipo),
+		Jogadores:   e.Jogadores,
+		Respondidas: e.Respondidas,
+		Conectados:  e.Conectados,
+	}
+	if e.Questao != nil {
+		ev.Questao = e.Questao.questao()
+		ev.Numero = e.Questao.Numero
+		ev.Total = e.Questao.Total
+		if e.Questao.Prazo != nil {
+			ev.Prazo = *e.Questao.Prazo
+		}
+	}
+	if c := e.Correcao; c != nil {
+		ev.Questao.Resposta = c.RespostaCorreta
+		ev.Questao.Explicacao = c.Explicacao
+		if ev.Questao.TipoQuestao() == quiz.TipoMultiplasRespostas {
+			ev.Questao.Respostas = c.OpcoesCorretas
+		}
+		if c.Respondeu {
+			ev.Resposta = &sala.Resposta{
+				Correta: c.Correta,
+				Pontos:  c.Pontos,
+				Duracao: time.Duration(c.DuracaoSegs * float64(time.Second)),
+			}
+		}
+	}
+	for _, p := range e.Placar {
+		ev.Placar = append(ev.Placar, sala.Posicao(p))
+	}
+	return ev
+}
@@ -0,0 +1,173 @@
+package servidor
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+
+	"quiz_go/internal/sala"
+)
+
+// salaAberta é uma sala.Sala no servidor, com a chave que identifica o
+// anfitrião.
+type salaAberta struct {
+	sala   *sala.Sala
+	chave  string
+	criada time.Time
+}
+
+func (s *Servidor) criarSala(w http.ResponseWriter, r *http.Request) {
+	var pedido pedidoSala
+	if err := lerJSON(w, r, &pedido); err != nil {
+		responderErro(w, http.StatusBadRequest, err)
+		return
+	}
+	op, err := pedido.opcoes()
+	if err != nil {
+		responderErro(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.limparSalas()
+
+	nova, err := sala.Nova(s.quiz, op)
+	if err != nil {
+		responderErro(w, http.StatusBadRequest, err)
+		return
+	}
+	chave := make([]byte, 16)
+	rand.Read(chave)
+	aberta := &salaAberta{sala: nova, chave: hex.EncodeToString(chave), criada: time.Now()}
+
+	s.mu.Lock()
+	codigo := sala.NovoCodigo()
+	for s.salas[codigo] != nil {
+		codigo = sala.NovoCodigo()
+	}
+	s.salas[codigo] = aberta
+	s.mu.Unlock()
+
+	responderJSON(w, http.StatusCreated, salaCriada{Codigo: codigo, Chave: aberta.chave, Total: nova.Total()})
+}
+
+// conectarSala liga um jogador (?nome=) ou o anfitrião (?chave=) à sala por
+// WebSocket. O servidor envia os eventos da partida como eventoSala e recebe
+// os pedidos como mensagemSala.
+func (s *Servidor) conectarSala(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	aberta := s.salas[strings.ToUpper(r.PathValue("codigo"))]
+	s.mu.Unlock()
+	if aberta == nil {
+		responderErro(w, http.StatusNotFound, errors.New("sala não encontrada"))
+		return
+	}
+
+	var p *sala.Participante
+	chave := r.URL.Query().Get("chave")
+	anfitriao := chave != ""
+	if anfitriao {
+		if subtle.ConstantTimeCompare([]byte(chave), []byte(aberta.chave)) != 1 {
+			responderErro(w, http.StatusForbidden, errors.New("chave do anfitrião inválida"))
+			return
+		}
+		p = aberta.sala.Assistir()
+	} else {
+		var err error
+		p, err = aberta.sala.Entrar(r.URL.Query().Get("nome"))
+		if err != nil {
+			responderErro(w, http.StatusConflict, err)
+			return
+		}
+	}
+	defer p.Sair()
+
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.CloseNow()
+
+	ctx, cancelar := context.WithCancel(r.Context())
+	defer cancelar()
+	go func() {
+		defer cancelar()
+		lerMensagens(ctx, conn, aberta.sala, p, anfitriao)
+	}()
+
+	for {
+		select {
+		case ev, ok := <-p.Eventos():
+			if !ok {
+				conn.Close(websocket.StatusNormalClosure, "fim da partida")
+				return
+			}
+			if err := wsjson.Write(ctx, conn, publicarEvento(ev)); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// lerMensagens atende os pedidos do cliente até a conexão fechar. Pedidos
+// recusados voltam como um eventoSala de erro.
+func lerMensagens(ctx context.Context, conn *websocket.Conn, s *sala.Sala, p *sala.Participante, anfitriao bool) {
+	for {
+		var m mensagemSala
+		if err := wsjson.Read(ctx, conn, &m); err != nil {
+			return
+		}
+
+		var err error
+		switch m.Tipo {
+		case "iniciar":
+			if !anfitriao {
+				err = errors.New("só o anfitrião pode iniciar a partida")
+			} else {
+				err = s.Iniciar()
+			}
+		case "responder":
+			err = p.Responder(m.escolhidas())
+		default:
+			err = fmt.Errorf("mensagem '%s' desconhecida", m.Tipo)
+		}
+		if err != nil {
+			_ = wsjson.Write(ctx, conn, eventoSala{Tipo: "erro", Erro: err.Error()})
+		}
+	}
+}
+
+// limparSalas remove as salas terminadas e as que ficaram sem ninguém
+// conectado por mais tempo que Inatividade.
+func (s *Servidor) limparSalas() {
+	limite := time.Now().Add(-s.Inatividade)
+	s.removerSalas(func(a *salaAberta) bool {
+		return a.sala.Fase() == sala.FaseFim || (a.sala.Conectados() == 0 && a.criada.Before(limite))
+	})
+}
+
+func (s *Servidor) removerSalas(escolher func(*salaAberta) bool) {
+	var removidas []*salaAberta
+	s.mu.Lock()
+	for codigo, a := range s.salas {
+		if escolher(a) {
+			removidas = append(removidas, a)
+			delete(s.salas, codigo)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, a := range removidas {
+		a.sala.Encerrar()
+	}
+}
@@ -0,0 +1,326 @@
+// Package servidor expõe o quiz numa API HTTP/JSON. As sessões ficam no
+// servidor: o cliente recebe as questões sem a resposta e só vê a correção
+// depois de responder. As salas de partida em grupo (veja o pacote sala)
+// usam WebSocket, e ClienteSala as acessa do terminal.
+package servidor
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"sync"
+	"time"
+
+	"quiz_go/internal/quiz"
+	"quiz_go/internal/stats"
+)
+
+//go:embed web
+var web embed.FS
+
+// paginas são os arquivos da página do quiz, servidos a partir da raiz.
+var paginas, _ = fs.Sub(web, "web")
+
+// InatividadePadrao é quanto tempo uma sessão sem requisições é mantida.
+const InatividadePadrao = time.Hour
+
+// limiteCorpo limita o tamanho do JSON aceito nas requisições.
+const limiteCorpo = 64 << 10
+
+// Servidor atende a API do quiz. Veja Novo para as rotas.
+type Servidor struct {
+	quiz *quiz.Quiz
+	mux  *http.ServeMux
+
+	// Inatividade é quanto tempo uma sessão sem requisições é mantida. As
+	// sessões inativas são interrompidas quando outra sessão é criada.
+	Inatividade time.Duration
+
+	mu      sync.Mutex
+	sessoes map[string]*sessao
+	salas   map[string]*salaAberta
+}
+
+// sessao é uma quiz.Sessao em andamento no servidor.
+type sessao struct {
+	mu       sync.Mutex
+	sessao   *quiz.Sessao
+	cancelar context.CancelFunc
+	acesso   time.Time
+	fim      bool         // não há mais questões para responder
+	resumo   *quiz.Resumo // preenchido quando o quiz é encerrado
+}
+
+// Novo cria o servidor da API e da página do quiz (veja web/), com as rotas:
+//
+//	GET    /                             página do quiz no navegador
+//	GET    /api/categorias               categorias das questões
+//	GET    /api/dificuldades             níveis de dificuldade
+//	GET    /api/modos                    modos de jogo
+//	POST   /api/sessoes                  inicia uma sessão com uma seleção
+//	GET    /api/sessoes/{id}/questao     questão atual, sem a resposta
+//	POST   /api/sessoes/{id}/resposta    responde e recebe a correção
+//	GET    /api/sessoes/{id}/resumo      resultado do quiz terminado
+//	DELETE /api/sessoes/{id}             interrompe a sessão
+//	GET    /api/estatisticas             estatísticas do jogador
+//	POST   /api/salas                    abre uma sala de partida em grupo
+//	GET    /api/salas/{codigo}/ws        WebSocket da sala (veja conectarSala)
+func Novo(q *quiz.Quiz) *Servidor {
+	s := &Servidor{
+		quiz:        q,
+		mux:         http.NewServeMux(),
+		Inatividade: InatividadePadrao,
+		sessoes:     make(map[string]*sessao),
+		salas:       make(map[string]*salaAberta),
+	}
+	s.mux.HandleFunc("GET /api/categorias", s.listarCategorias)
+	s.mux.HandleFunc("GET /api/dificuldades", s.listarDificuldades)
+	s.mux.HandleFunc("GET /api/modos", s.listarModos)
+	s.mux.HandleFunc("POST /api/sessoes", s.criarSessao)
+	s.mux.HandleFunc("GET /api/sessoes/{id}/questao", s.questaoAtual)
+	s.mux.HandleFunc("POST /api/sessoes/{id}/resposta", s.responder)
+	s.mux.HandleFunc("GET /api/sessoes/{id}/resumo", s.resumo)
+	s.mux.HandleFunc("DELETE /api/sessoes/{id}", s.encerrarSessao)
+	s.mux.HandleFunc("GET /api/estatisticas", s.estatisticas)
+	s.mux.HandleFunc("POST /api/salas", s.criarSala)
+	s.mux.HandleFunc("GET /api/salas/{codigo}/ws", s.conectarSala)
+	s.mux.HandleFunc("GET /api/", func(w http.ResponseWriter, r *http.Request) {
+		responderErro(w, http.StatusNotFound, errors.New("rota não encontrada"))
+	})
+	s.mux.Handle("GET /", http.FileServerFS(paginas))
+	return s
+}
+
+func (s *Servidor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Fechar interrompe todas as sessões em andamento e encerra as salas. As
+// respostas já dadas nas sessões ficam no histórico.
+func (s *Servidor) Fechar() {
+	s.remover(func(*sessao) bool { return true })
+	s.removerSalas(func(*salaAberta) bool { return true })
+}
+
+func (s *Servidor) listarCategorias(w http.ResponseWriter, r *http.Request) {
+	responderJSON(w, http.StatusOK, map[string][]string{"categorias": quiz.Categorias})
+}
+
+func (s *Servidor) listarDificuldades(w http.ResponseWriter, r *http.Request) {
+	responderJSON(w, http.StatusOK, map[string][]string{"dificuldades": quiz.Dificuldades})
+}
+
+func (s *Servidor) listarModos(w http.ResponseWriter, r *http.Request) {
+	modos := []modoJSON{}
+	for _, m := range quiz.Modos {
+		if m.SoComIA() && !s.quiz.IAAtiva() {
+			continue
+		}
+		modos = append(modos, modoJSON{Modo: m, Nome: m.Nome(), EmGrupo: !m.Pessoal() && m != quiz.ModoProva})
+	}
+	responderJSON(w, http.StatusOK, map[string][]modoJSON{"modos": modos})
+}
+
+func (s *Servidor) criarSessao(w http.ResponseWriter, r *http.Request) {
+	var pedido pedidoSessao
+	if err := lerJSON(w, r, &pedido); err != nil {
+		responderErro(w, http.StatusBadRequest, err)
+		return
+	}
+	sel, err := pedido.selecao()
+	if err != nil {
+		responderErro(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.limparInativas()
+
+	// A geração continua em segundo plano enquanto a sessão existir, e não
+	// termina junto com esta requisição.
+	ctx, cancelar := context.WithCancel(context.Background())
+	qs := s.quiz.IniciarSessao(ctx, sel)
+	if qs.Total() == 0 {
+		cancelar()
+		responderErro(w, http.StatusUnprocessableEntity, errors.New("nenhuma questão encontrada para esta seleção"))
+		return
+	}
+
+	id := stats.NovaSessao()
+	s.mu.Lock()
+	s.sessoes[id] = &sessao{sessao: qs, cancelar: cancelar, acesso: time.Now()}
+	s.mu.Unlock()
+
+	responderJSON(w, http.StatusCreated, sessaoCriada{
+		ID:               id,
+		Total:            qs.Total(),
+		DuracaoProvaSegs: qs.DuracaoProva().Seconds(),
+		FimProva:         horario(qs.FimProva()),
+	})
+}
+
+func (s *Servidor) questaoAtual(w http.ResponseWriter, r *http.Request) {
+	ss, ok := s.buscar(w, r)
+	if !ok {
+		return
+	}
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if !ss.fim && !ss.sessao.ProvaEncerrada() {
+		if questao, ok := ss.sessao.Proxima(); ok {
+			responderJSON(w, http.StatusOK, proximaQuestao{questaoPublica: publicar(ss.sessao, questao)})
+			return
+		}
+	}
+	ss.fim = true
+	responderJSON(w, http.StatusOK, proximaQuestao{Fim: true})
+}
+
+func (s *Servidor) responder(w http.ResponseWriter, r *http.Request) {
+	ss, ok := s.buscar(w, r)
+	if !ok {
+		return
+	}
+	var pedido pedidoResposta
+	if err := lerJSON(w, r, &pedido); err != nil {
+		responderErro(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	var resultado quiz.Resultado
+	var err error
+	if pedido.Esgotado {
+		resultado, err = ss.sessao.EsgotarTempo()
+	} else {
+		resultado, err = ss.sessao.Responder(pedido.escolhidas())
+	}
+	if err != nil {
+		responderErro(w, http.StatusConflict, err)
+		return
+	}
+	if ss.sessao.ProvaEncerrada() || ss.sessao.Restantes() == 0 {
+		ss.fim = true
+	}
+	responderJSON(w, http.StatusOK, correcao(resultado, ss.sessao, ss.fim))
+}
+
+func (s *Servidor) resumo(w http.ResponseWriter, r *http.Request) {
+	ss, ok := s.buscar(w, r)
+	if !ok {
+		return
+	}
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if !ss.fim {
+		responderErro(w, http.StatusConflict, errors.New("o quiz ainda não terminou"))
+		return
+	}
+	if ss.resumo == nil {
+		resumo := ss.sessao.Encerrar()
+		ss.resumo = &resumo
+		ss.cancelar()
+	}
+	responderJSON(w, http.StatusOK, publicarResumo(*ss.resumo))
+}
+
+func (s *Servidor) encerrarSessao(w http.ResponseWriter, r *http.Request) {
+	ss, ok := s.buscar(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	delete(s.sessoes, r.PathValue("id"))
+	s.mu.Unlock()
+
+	ss.interromper()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Servidor) estatisticas(w http.ResponseWriter, r *http.Request) {
+	responderJSON(w, http.StatusOK, publicarEstatisticas(s.quiz.Estatisticas()))
+}
+
+// buscar retorna a sessão do caminho, ou responde 404.
+func (s *Servidor) buscar(w http.ResponseWriter, r *http.Request) (*sessao, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ss, ok := s.sessoes[r.PathValue("id")]
+	if !ok {
+		responderErro(w, http.StatusNotFound, errors.New("sessão não encontrada"))
+		return nil, false
+	}
+	ss.acesso = time.Now()
+	return ss, true
+}
+
+// limparInativas interrompe e remove as sessões sem requisições há mais
+// tempo que Inatividade.
+func (s *Servidor) limparInativas() {
+	limite := time.Now().Add(-s.Inatividade)
+	s.remover(func(ss *sessao) bool { return ss.acesso.Before(limite) })
+}
+
+// remover interrompe e remove as sessões escolhidas. A interrupção fica
+// fora do bloqueio do mapa, pois pode esperar uma questão sendo gerada.
+func (s *Servidor) remover(escolher func(*sessao) bool) {
+	var removidas []*sessao
+	s.mu.Lock()
+	for id, ss := range s.sessoes {
+		if escolher(ss) {
+			removidas = append(removidas, ss)
+			delete(s.sessoes, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, ss := range removidas {
+		ss.interromper()
+	}
+}
+
+// interromper cancela a geração e guarda as respostas de um quiz que não
+// foi encerrado. O cancelamento vem antes, para liberar uma requisição que
+// esteja esperando a próxima questão.
+func (ss *sessao) interromper() {
+	ss.cancelar()
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.resumo == nil {
+		ss.sessao.Interromper()
+	}
+}
+
+func lerJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, limiteCorpo))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("JSON inválido: %v", err)
+	}
+	return nil
+}
+
+func responderJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func responderErro(w http.ResponseWriter, status int, err error) {
+	responderJSON(w, status, map[string]string{"erro": err.Error()})
+}
+
+// horario retorna nil para o horário zero, que não vai no JSON.
+func horario(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,398 @@
+package servidor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"quiz_go/internal/config"
+	"quiz_go/internal/quiz"
+	"quiz_go/internal/sala"
+)
+
+// novoServidorTeste sobe a API com um quiz offline, que usa só o pacote
+// embutido e grava as estatísticas num diretório temporário.
+func novoServidorTeste(t *testing.T) (*httptest.Server, *quiz.Quiz) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := config.Padrao()
+	cfg.Gerador = quiz.GeradorNomeEstatico
+	cfg.ArquivoStats = filepath.Join(dir, "stats.json")
+	cfg.ArquivoGeradas = ""
+
+	q := quiz.NewQuiz(cfg)
+	s := Novo(q)
+	ts := httptest.NewServer(s)
+	t.Cleanup(func() {
+		ts.Close()
+		s.Fechar()
+	})
+	return ts, q
+}
+
+// requisitar faz a requisição com o corpo em JSON e decodifica a resposta
+// em destino, se informado. Retorna o status e o corpo lido.
+func requisitar(t *testing.T, metodo, url string, corpo, destino any) (int, string) {
+	t.Helper()
+	var leitor io.Reader
+	if corpo != nil {
+		dados, err := json.Marshal(corpo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		leitor = bytes.NewReader(dados)
+	}
+	req, err := http.NewRequest(metodo, url, leitor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	dados, _ := io.ReadAll(resp.Body)
+	if destino != nil && resp.StatusCode < 300 {
+		if err := json.Unmarshal(dados, destino); err != nil {
+			t.Fatalf("%s %s: %v\n%s", metodo, url, err, dados)
+		}
+	}
+	return resp.StatusCode, string(dados)
+}
+
+// questaoRecebida decodifica proximaQuestao, cuja questão embutida é um
+// ponteiro que o JSON não preenche.
+type questaoRecebida struct {
+	Fim bool `json:"fim"`
+	questaoPublica
+}
+
+func TestSessaoCompleta(t *testing.T) {
+	ts, q := novoServidorTeste(t)
+
+	pacote, err := quiz.CarregarPacote()
+	if err != nil {
+		t.Fatal(err)
+	}
+	porID := make(map[int]quiz.Questao)
+	for _, questao := range pacote {
+		porID[questao.ID] = questao
+	}
+
+	var criada sessaoCriada
+	status, corpo := requisitar(t, "POST", ts.URL+"/api/sessoes", map[string]any{
+		"modo": "quick", "dificuldade": "facil", "categoria": "SINTAXE", "tipo": quiz.TipoMultiplaEscolha, "quantidade": 2,
+	}, &criada)
+	if status != http.StatusCreated || criada.Total != 2 {
+		t.Fatalf("criar sessão: %d %s", status, corpo)
+	}
+	sessao := ts.URL + "/api/sessoes/" + criada.ID
+
+	status, _ = requisitar(t, "GET", sessao+"/resumo", nil, nil)
+	if status != http.StatusConflict {
+		t.Errorf("resumo antes do fim: status %d, esperado 409", status)
+	}
+
+	for i, acertar := range []bool{true, false} {
+		var proxima questaoRecebida
+		_, corpo := requisitar(t, "GET", sessao+"/questao", nil, &proxima)
+		if proxima.Fim || proxima.Numero != i+1 {
+			t.Fatalf("questão %d: %s", i+1, corpo)
+		}
+		if strings.Contains(corpo, `"resposta"`) || strings.Contains(corpo, `"explicacao"`) {
+			t.Errorf("a questão não deveria trazer a resposta: %s", corpo)
+		}
+
+		// Pedir de novo entrega a mesma questão.
+		var repetida questaoRecebida
+		requisitar(t, "GET", sessao+"/questao", nil, &repetida)
+		if repetida.ID != proxima.ID {
+			t.Errorf("questão %d mudou para %d antes da resposta", proxima.ID, repetida.ID)
+		}
+
+		original := porID[proxima.ID]
+		escolhida := original.Resposta
+		if !acertar {
+			for _, opcao := range original.Opcoes {
+				if opcao != original.Resposta {
+					escolhida = opcao
+					break
+				}
+			}
+		}
+
+		var c correcaoJSON
+		status, corpo = requisitar(t, "POST", sessao+"/resposta", map[string]any{"escolhidas": []string{escolhida}}, &c)
+		if status != http.StatusOK || c.Correta != acertar || c.RespostaCorreta != original.Resposta || c.Explicacao == "" {
+			t.Errorf("correção da questão %d: %d %s", i+1, status, corpo)
+		}
+		if c.Fim != (i == 1) {
+			t.Errorf("fim = %v na questão %d", c.Fim, i+1)
+		}
+	}
+
+	status, _ = requisitar(t, "POST", sessao+"/resposta", map[string]any{"resposta": "len"}, nil)
+	if status != http.StatusConflict {
+		t.Errorf("resposta sem questão: status %d, esperado 409", status)
+	}
+
+	var resumo resumoJSON
+	status, corpo = requisitar(t, "GET", sessao+"/resumo", nil, &resumo)
+	if status != http.StatusOK || resumo.Acertos != 1 || resumo.Respondidas != 2 || resumo.Percentual != 50 {
+		t.Errorf("resumo: %d %s", status, corpo)
+	}
+	// Pedir o resumo de novo não conta o quiz duas vezes.
+	requisitar(t, "GET", sessao+"/resumo", nil, nil)
+
+	var e estatisticasJSON
+	requisitar(t, "GET", ts.URL+"/api/estatisticas", nil, &e)
+	if e.TotalQuizzes != 1 || e.TotalAcertos != 1 || len(e.PorCategoria) != 1 || e.PorCategoria[0].Nome != "sintaxe" {
+		t.Errorf("estatísticas = %+v", e)
+	}
+	if q.Estatisticas().TotalQuizzes != 1 {
+		t.Error("as estatísticas do quiz não foram atualizadas")
+	}
+}
+
+func TestSessaoInterrompida(t *testing.T) {
+	ts, q := novoServidorTeste(t)
+
+	var criada sessaoCriada
+	requisitar(t, "POST", ts.URL+"/api/sessoes", map[string]any{"modo": "quick"}, &criada)
+	sessao := ts.URL + "/api/sessoes/" + criada.ID
+
+	requisitar(t, "GET", sessao+"/questao", nil, nil)
+	requisitar(t, "POST", sessao+"/resposta", map[string]any{"resposta": "?"}, nil)
+
+	if status, _ := requisitar(t, "DELETE", sessao, nil, nil); status != http.StatusNoContent {
+		t.Errorf("DELETE: status %d, esperado 204", status)
+	}
+	if status, _ := requisitar(t, "GET", sessao+"/questao", nil, nil); status != http.StatusNotFound {
+		t.Errorf("sessão removida: status %d, esperado 404", status)
+	}
+
+	e := q.Estatisticas()
+	if e.TotalQuizzes != 0 || len(e.Respostas) != 1 {
+		t.Errorf("estatísticas = %d quizzes e %d respostas, esperado 0 e 1", e.TotalQuizzes, len(e.Respostas))
+	}
+}
+
+func TestSelecaoInvalida(t *testing.T) {
+	ts, _ := novoServidorTeste(t)
+
+	casos := []struct {
+		corpo string
+		erro  string
+	}{
+		{`{"modo": "tudo"}`, "modo 'tudo' desconhecido"},
+		{`{"categoria": "culinária"}`, "categoria 'culinária' desconhecida"},
+		{`{"dificuldade": "extrema"}`, "dificuldade 'extrema' inválida"},
+		{`{"modo": "quick", "extra": 1}`, "JSON inválido"},
+	}
+	for _, c := range casos {
+		resp, err := http.Post(ts.URL+"/api/sessoes", "application/json", strings.NewReader(c.corpo))
+		if err != nil {
+			t.Fatal(err)
+		}
+		dados, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(dados), c.erro) {
+			t.Errorf("%s: %d %s, esperado 400 com %q", c.corpo, resp.StatusCode, dados, c.erro)
+		}
+	}
+}
+
+func TestListas(t *testing.T) {
+	ts, _ := novoServidorTeste(t)
+
+	var categorias map[string][]string
+	requisitar(t, "GET", ts.URL+"/api/categorias", nil, &categorias)
+	if len(categorias["categorias"]) != len(quiz.Categorias) {
+		t.Errorf("categorias = %v", categorias)
+	}
+
+	var dificuldades map[string][]string
+	requisitar(t, "GET", ts.URL+"/api/dificuldades", nil, &dificuldades)
+	if strings.Join(dificuldades["dificuldades"], ",") != "facil,medio,dificil" {
+		t.Errorf("dificuldades = %v", dificuldades)
+	}
+
+	// Sem a IA, os modos que dependem dela ficam de fora, como no menu.
+	var modos map[string][]modoJSON
+	requisitar(t, "GET", ts.URL+"/api/modos", nil, &modos)
+	if len(modos["modos"]) == 0 || modos["modos"][0].Nome != "Todas as questões" {
+		t.Errorf("modos = %v", modos)
+	}
+	for _, m := range modos["modos"] {
+		if m.Modo.SoComIA() {
+			t.Errorf("modo %s listado sem a IA", m.Modo)
+		}
+	}
+}
+
+func TestTempoEsgotadoPeloCliente(t *testing.T) {
+	ts, _ := novoServidorTeste(t)
+
+	var criada sessaoCriada
+	requisitar(t, "POST", ts.URL+"/api/sessoes", map[string]any{"modo": "quick", "tipo": quiz.TipoMultiplaEscolha, "quantidade": 1}, &criada)
+	sessao := ts.URL + "/api/sessoes/" + criada.ID
+	requisitar(t, "GET", sessao+"/questao", nil, nil)
+
+	var c correcaoJSON
+	requisitar(t, "POST", sessao+"/resposta", map[string]any{"esgotado": true}, &c)
+	if !c.TempoEsgotado || c.Correta || len(c.OpcoesCorretas) == 0 || !c.Fim {
+		t.Errorf("correção = %+v", c)
+	}
+
+	var resumo resumoJSON
+	requisitar(t, "GET", sessao+"/resumo", nil, &resumo)
+	if resumo.SemTempo != 1 {
+		t.Errorf("sem tempo = %d, esperado 1", resumo.SemTempo)
+	}
+}
+
+func TestPagina(t *testing.T) {
+	ts, _ := novoServidorTeste(t)
+
+	casos := []struct {
+		caminho, tipo, trecho string
+	}{
+		{"/", "text/html", `<script src="app.js">`},
+		{"/app.js", "javascript", "/sessoes/"},
+		{"/estilo.css", "text/css", ".selo"},
+		{"/sala.html", "text/html", `<script src="sala.js">`},
+		{"/sala.js", "javascript", "/ws?"},
+	}
+	for _, c := range casos {
+		resp, err := http.Get(ts.URL + c.caminho)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dados, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || !strings.Contains(resp.Header.Get("Content-Type"), c.tipo) || !strings.Contains(string(dados), c.trecho) {
+			t.Errorf("GET %s: %d %s", c.caminho, resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+	}
+
+	// Rotas desconhecidas da API respondem em JSON, não com a página.
+	status, corpo := requisitar(t, "GET", ts.URL+"/api/nada", nil, nil)
+	if status != http.StatusNotFound || !strings.Contains(corpo, `"erro"`) {
+		t.Errorf("GET /api/nada: %d %s", status, corpo)
+	}
+}
+
+// proximoEvento lê eventos do cliente até um do tipo pedido.
+func proximoEvento(t *testing.T, c *ClienteSala, tipo sala.TipoEvento) sala.Evento {
+	t.Helper()
+	ctx, cancelar := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelar()
+	for {
+		ev, err := c.Proximo(ctx)
+		if err != nil {
+			t.Fatalf("esperando %s: %v", tipo, err)
+		}
+		if ev.Tipo == tipo {
+			return ev
+		}
+	}
+}
+
+func TestSalaPorWebSocket(t *testing.T) {
+	ts, _ := novoServidorTeste(t)
+	ctx := context.Background()
+
+	questoes := []quiz.Questao{
+		{ID: 1, Tipo: quiz.TipoMultiplasRespostas, Questao: "Quais são tipos inteiros?", Opcoes: []string{"int", "string", "uint8", "bool"},
+			Respostas: []string{"int", "uint8"}, Explicacao: "int e uint8 são inteiros.", Dificuldade: "facil", Categoria: "tipos"},
+	}
+	codigo, chave, err := CriarSala(ctx, ts.URL, sala.Opcoes{Questoes: questoes, TempoQuestao: 5 * time.Second, Pausa: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := EntrarSala(ctx, ts.URL, "NADA00", "Ana"); err == nil || !strings.Contains(err.Error(), "sala não encontrada") {
+		t.Errorf("sala inexistente: %v", err)
+	}
+	if _, err := AssistirSala(ctx, ts.URL, codigo, "errada"); err == nil || !strings.Contains(err.Error(), "chave") {
+		t.Errorf("chave errada: %v", err)
+	}
+
+	anfitriao, err := AssistirSala(ctx, ts.URL, codigo, chave)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer anfitriao.Fechar()
+	ana, err := EntrarSala(ctx, ts.URL, strings.ToLower(codigo), "Ana")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ana.Fechar()
+	if _, err := EntrarSala(ctx, ts.URL, codigo, "Ana"); err == nil || !strings.Contains(err.Error(), "já há um jogador") {
+		t.Errorf("nome repetido: %v", err)
+	}
+
+	// Só o anfitrião inicia a partida.
+	if err := ana.Iniciar(ctx); err != nil {
+		t.Fatal(err)
+	}
+	limite, cancelar := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelar()
+	for {
+		_, err := ana.Proximo(limite)
+		if err != nil {
+			var recusado *ErroSala
+			if !errors.As(err, &recusado) {
+				t.Fatalf("iniciar pelo jogador: %v", err)
+			}
+			break
+		}
+	}
+
+	if err := anfitriao.Iniciar(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	q := proximoEvento(t, ana, sala.EventoQuestao)
+	if q.Questao.Questao != questoes[0].Questao || q.Questao.Resposta != "" || len(q.Questao.Respostas) != 0 || q.Prazo.IsZero() {
+		t.Errorf("questão recebida = %+v", q)
+	}
+	if err := ana.Responder(ctx, []string{"uint8", "int"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := proximoEvento(t, ana, sala.EventoCorrecao)
+	if c.Resposta == nil || !c.Resposta.Correta || c.Resposta.Pontos < sala.PontosMaximos/2 {
+		t.Errorf("correção = %+v", c.Resposta)
+	}
+	if c.Questao.RespostaEsperada() != "int; uint8" || c.Questao.Explicacao == "" {
+		t.Errorf("a correção deveria trazer a resposta: %+v", c.Questao)
+	}
+	if len(c.Placar) != 1 || c.Placar[0].Nome != "Ana" || c.Placar[0].Pontos != c.Resposta.Pontos {
+		t.Errorf("placar = %+v", c.Placar)
+	}
+
+	fim := proximoEvento(t, anfitriao, sala.EventoFim)
+	if len(fim.Placar) != 1 || fim.Placar[0].Acertos != 1 {
+		t.Errorf("placar final = %+v", fim.Placar)
+	}
+	if _, err := anfitriao.Proximo(ctx); err != io.EOF {
+		t.Errorf("depois do fim: %v, esperado io.EOF", err)
+	}
+}
+
+func TestSalaRecusaModoPessoal(t *testing.T) {
+	ts, _ := novoServidorTeste(t)
+	status, corpo := requisitar(t, "POST", ts.URL+"/api/salas", map[string]any{"modo": "review"}, nil)
+	if status != http.StatusBadRequest || !strings.Contains(corpo, "histórico") {
+		t.Errorf("modo review: %d %s", status, corpo)
+	}
+}
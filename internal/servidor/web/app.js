@@ -0,0 +1,318 @@
+// Página do quiz: joga uma sessão pela API em /api, com o mesmo fluxo do
+// terminal. Usa as funções de comum.js.
+"use strict";
+
+const estado = {
+	sessao: null,    // id da sessão em andamento
+	total: 0,
+	questao: null,   // questão mostrada
+	escolhidas: [],  // opções marcadas na questão mostrada
+	acertos: 0,
+	contagem: null,  // intervalo da contagem regressiva
+	respondendo: false,
+};
+
+function mostrar(secao) {
+	for (const id of ["inicio", "questao", "aguardando", "resultados", "estatisticas"]) {
+		$(id).hidden = id !== secao;
+	}
+}
+
+async function carregarInicio() {
+	const [modos, categorias, niveis] = await Promise.all([
+		api("GET", "/modos"), api("GET", "/categorias"), api("GET", "/dificuldades"),
+	]);
+	const form = $("form-inicio");
+	for (const m of modos.modos) {
+		const opcao = elemento("option", m.nome);
+		opcao.value = m.modo;
+		form.modo.append(opcao);
+	}
+	for (const c of categorias.categorias) {
+		const opcao = elemento("option", c);
+		opcao.value = c;
+		form.categoria.append(opcao);
+	}
+	for (const d of niveis.dificuldades) {
+		const opcao = elemento("option", (dificuldades[d] || [d])[0]);
+		opcao.value = d;
+		form.dificuldade.append(opcao);
+	}
+}
+
+async function iniciar(evento) {
+	evento.preventDefault();
+	limparErro();
+	const form = $("form-inicio");
+	const pedido = {
+		modo: form.modo.value,
+		categoria: form.categoria.value,
+		dificuldade: form.dificuldade.value,
+	};
+	if (form.quantidade.value) {
+		pedido.quantidade = Number(form.quantidade.value);
+	}
+	try {
+		const criada = await api("POST", "/sessoes", pedido);
+		estado.sessao = criada.id;
+		estado.total = criada.total;
+		estado.acertos = 0;
+		await proxima();
+	} catch (err) {
+		mostrarErro(err);
+	}
+}
+
+async function proxima() {
+	limparErro();
+	// A questão pode estar sendo gerada pela IA; o aviso só aparece se demorar.
+	const aviso = setTimeout(() => mostrar("aguardando"), 300);
+	try {
+		const resp = await api("GET", `/sessoes/${estado.sessao}/questao`);
+		clearTimeout(aviso);
+		if (resp.fim) {
+			await resultados();
+		} else {
+			mostrarQuestao(resp);
+		}
+	} catch (err) {
+		clearTimeout(aviso);
+		mostrar("questao");
+		mostrarErro(err);
+	}
+}
+
+function mostrarQuestao(q) {
+	estado.questao = q;
+	estado.respondendo = false;
+
+	mostrarCabecalho(q);
+	atualizarProgresso(q.numero - 1, q.total);
+
+	mostrarEnunciado(q);
+	montarOpcoes(q, responder);
+
+	$("correcao").hidden = true;
+	$("proxima").hidden = true;
+	iniciarContagem(q.prazo);
+	mostrar("questao");
+}
+
+function atualizarProgresso(respondidas, total) {
+	$("barra").style.width = `${(100 * respondidas) / Math.max(1, total)}%`;
+	$("progresso-texto").textContent = `📊 Progresso: ${respondidas}/${total} questões | Acertos: ${estado.acertos}`;
+}
+
+// iniciarContagem mostra o tempo restante da questão e, quando ele acaba,
+// avisa a API que a questão ficou sem resposta.
+function iniciarContagem(prazo) {
+	pararContagem();
+	if (!prazo) {
+		return;
+	}
+	const fim = new Date(prazo).getTime();
+	const atualizar = () => {
+		const restante = Math.max(0, Math.ceil((fim - Date.now()) / 1000));
+		$("contagem").textContent = `⏰ ${restante}s`;
+		if (restante === 0) {
+			pararContagem();
+			responder({ esgotado: true });
+		}
+	};
+	$("contagem").hidden = false;
+	atualizar();
+	estado.contagem = setInterval(atualizar, 250);
+}
+
+function pararContagem() {
+	clearInterval(estado.contagem);
+	estado.contagem = null;
+	$("contagem").hidden = true;
+}
+
+async function responder(pedido) {
+	if (estado.respondendo) {
+		return;
+	}
+	estado.respondendo = true;
+	estado.escolhidas = pedido.escolhidas || [];
+	pararContagem();
+	desativarOpcoes();
+	try {
+		const c = await api("POST", `/sessoes/${estado.sessao}/resposta`, pedido);
+		mostrarCorrecao(c);
+	} catch (err) {
+		mostrarErro(err);
+	}
+}
+
+function mostrarCorrecao(c) {
+	estado.acertos = c.acertos;
+	atualizarProgresso(c.numero, estado.questao.total);
+
+	const veredito = $("veredito");
+	if (c.tempo_esgotado) {
+		veredito.textContent = `⏰ Tempo esgotado! A resposta correta é: ${c.resposta_correta}`;
+		veredito.className = "esgotado";
+	} else if (c.correta) {
+		veredito.textContent = "✅ Resposta correta! Parabéns!";
+		veredito.className = "certa";
+	} else {
+		veredito.textContent = `❌ Resposta incorreta! A resposta correta é: ${c.resposta_correta}`;
+		veredito.className = "errada";
+	}
+	textoComCodigo($("explicacao"), "💡 " + c.explicacao);
+
+	marcarOpcoes(c.opcoes_corretas, estado.escolhidas);
+
+	$("correcao").hidden = false;
+	$("proxima").textContent = c.fim ? "Ver resultados 🏆" : "Continuar ➡️";
+	$("proxima").hidden = false;
+	$("proxima").focus();
+}
+
+async function sair() {
+	if (!confirm("Sair do quiz? As respostas dadas ficam no histórico.")) {
+		return;
+	}
+	pararContagem();
+	try {
+		await api("DELETE", `/sessoes/${estado.sessao}`);
+	} catch (err) {
+		// A sessão pode já ter expirado; volta ao início de qualquer forma.
+	}
+	estado.sessao = null;
+	mostrar("inicio");
+}
+
+async function resultados() {
+	const r = await api("GET", `/sessoes/${estado.sessao}/resumo`);
+	const numeros = $("numeros");
+	numeros.replaceChildren();
+	if (r.sem_resposta > 0) {
+		numeros.append(elemento("li", `⏰ Fim do tempo da prova! ${r.sem_resposta} questões ficaram sem resposta.`));
+	}
+	numeros.append(
+		elemento("li", `📊 Você acertou ${r.acertos} de ${r.respondidas} questões`),
+		elemento("li", `📈 Percentual de acertos: ${r.percentual.toFixed(1)}%`),
+	);
+	if (r.sem_tempo > 0) {
+		numeros.append(elemento("li", `⏰ Sem resposta (tempo esgotado): ${r.sem_tempo}`));
+	}
+	if (r.pontos !== undefined) {
+		numeros.append(elemento("li", `🏅 Pontuação com bônus de velocidade: ${r.pontos.toFixed(1)}`));
+	}
+	numeros.append(
+		elemento("li", `⏱️ Tempo total: ${r.tempo_s.toFixed(1)} segundos`),
+		elemento("li", `⚡ Tempo médio por questão: ${(r.tempo_s / Math.max(1, r.respondidas - r.sem_resposta)).toFixed(1)} segundos`),
+	);
+	if (r.recorde) {
+		numeros.append(elemento("li", `🏆 Novo recorde no modo ${r.quadro}!`));
+	} else if (r.recorde_pessoal) {
+		numeros.append(elemento("li", `⭐ Sua melhor partida no modo ${r.quadro}!`));
+	}
+	if (r.erro_estatisticas) {
+		numeros.append(elemento("li", `❌ Não foi possível salvar as estatísticas: ${r.erro_estatisticas}`));
+	}
+
+	$("respostas").replaceChildren(...r.respostas.map((resposta, i) => {
+		const status = resposta.correta ? "✅" : resposta.tempo_esgotado ? "⏰" : "❌";
+		return elemento("li", `Questão ${i + 1}: ${status}`);
+	}));
+
+	$("mensagem").replaceChildren(...mensagemFinal(r.acertos, r.respondidas, r.percentual)
+		.map(([cor, texto]) => elemento("p", texto, "mensagem " + cor)));
+
+	const variacao = r.habilidade - r.habilidade_anterior;
+	$("habilidade").textContent = `🧭 Nível estimado: ${r.habilidade.toFixed(0)} ` +
+		`(${variacao >= 0 ? "+" : ""}${variacao.toFixed(0)}) | equivale às questões de nível ${r.nivel}`;
+
+	estado.sessao = null;
+	mostrar("resultados");
+}
+
+// mensagemFinal traz as mesmas mensagens de MostrarMensagemFinal no terminal.
+function mensagemFinal(acertos, total, percentual) {
+	if (acertos === total) {
+		return [
+			["verde", "🎉 PERFEITO! Você acertou todas as questões!"],
+			["verde", "🏆 Você é um verdadeiro expert em Go!"],
+			["verde", "🌟 Considerado um GoGuru!"],
+		];
+	}
+	if (percentual >= 80) {
+		return [
+			["verde", "🌟 Excelente! Você tem um ótimo conhecimento em Go!"],
+			["verde", "👏 Continue assim!"],
+			["azul", "🚀 Próximo nível: tente as questões difíceis!"],
+		];
+	}
+	if (percentual >= 60) {
+		return [
+			["amarelo", "👍 Muito bem! Você está no caminho certo!"],
+			["amarelo", "📚 Continue estudando para melhorar ainda mais!"],
+			["azul", "💡 Dica: revise os conceitos que errou!"],
+		];
+	}
+	if (percentual >= 40) {
+		return [
+			["amarelo", "😊 Bom começo! Você já sabe algumas coisas sobre Go!"],
+			["amarelo", "💪 Com mais estudo você chegará lá!"],
+			["azul", "📖 Recomendo focar nos fundamentos primeiro!"],
+		];
+	}
+	return [
+		["vermelho", "📖 Você precisa estudar mais sobre Go!"],
+		["vermelho", "💡 Que tal revisar a documentação oficial?"],
+		["amarelo", "🔗 Recursos recomendados: golang.org/doc, tour.golang.org e gobyexample.com"],
+	];
+}
+
+async function estatisticas() {
+	limparErro();
+	try {
+		const e = await api("GET", "/estatisticas");
+		$("totais").replaceChildren(
+			elemento("li", `🎮 Quizzes jogados: ${e.total_quizzes}`),
+			elemento("li", `✅ Acertos: ${e.total_acertos} de ${e.total_questoes}`),
+			elemento("li", `🏆 Melhor pontuação: ${e.melhor_score}`),
+			elemento("li", `📈 Média: ${e.media_percentual.toFixed(1)}%`),
+			elemento("li", `🧭 Nível estimado: ${e.habilidade.toFixed(0)} (${e.nivel})`),
+		);
+		if (e.ultimo_quiz) {
+			$("totais").append(elemento("li", `📅 Último quiz: ${e.ultimo_quiz}`));
+		}
+		tabela($("por-categoria"), e.por_categoria);
+		tabela($("por-dificuldade"), e.por_dificuldade);
+		mostrar("estatisticas");
+	} catch (err) {
+		mostrarErro(err);
+	}
+}
+
+function tabela(destino, desempenhos) {
+	const cabecalho = elemento("tr");
+	for (const titulo of ["", "Respondidas", "Acertos", "%", "Tempo médio"]) {
+		cabecalho.append(elemento("th", titulo));
+	}
+	destino.replaceChildren(cabecalho, ...desempenhos.map((d) => {
+		const linha = elemento("tr");
+		linha.append(
+			elemento("td", d.nome),
+			elemento("td", d.respondidas),
+			elemento("td", d.acertos),
+			elemento("td", d.percentual.toFixed(0) + "%"),
+			elemento("td", d.tempo_medio_s.toFixed(1) + "s"),
+		);
+		return linha;
+	}));
+}
+
+$("form-inicio").onsubmit = iniciar;
+$("ver-estatisticas").onclick = estatisticas;
+$("voltar").onclick = () => mostrar("inicio");
+$("proxima").onclick = proxima;
+$("sair").onclick = sair;
+$("jogar-novamente").onclick = () => mostrar("inicio");
+
+carregarInicio().catch(mostrarErro);
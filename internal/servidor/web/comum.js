@@ -0,0 +1,143 @@
+// Funções comuns às páginas do quiz. Os textos vindos da API entram sempre
+// como texto, nunca como HTML.
+"use strict";
+
+const $ = (id) => document.getElementById(id);
+
+const dificuldades = {
+	facil: ["🟢 Fácil", "facil"],
+	medio: ["🟡 Médio", "medio"],
+	dificil: ["🔴 Difícil", "dificil"],
+};
+
+// api faz a requisição e retorna o JSON, ou lança o erro que a API mandou.
+async function api(metodo, caminho, corpo) {
+	const opcoes = { method: metodo, headers: {} };
+	if (corpo !== undefined) {
+		opcoes.headers["Content-Type"] = "application/json";
+		opcoes.body = JSON.stringify(corpo);
+	}
+	const resp = await fetch("/api" + caminho, opcoes);
+	if (resp.status === 204) {
+		return null;
+	}
+	const dados = await resp.json().catch(() => ({}));
+	if (!resp.ok) {
+		throw new Error(dados.erro || `erro ${resp.status}`);
+	}
+	return dados;
+}
+
+function mostrarErro(err) {
+	$("erro").textContent = "⚠️ " + err.message;
+	$("erro").hidden = false;
+}
+
+function limparErro() {
+	$("erro").hidden = true;
+}
+
+function elemento(tag, texto, classe) {
+	const el = document.createElement(tag);
+	if (texto !== undefined) {
+		el.textContent = texto;
+	}
+	if (classe) {
+		el.className = classe;
+	}
+	return el;
+}
+
+// textoComCodigo preenche o destino com o texto, pondo os blocos entre ```
+// em <pre>, como ui.SepararBlocos faz no terminal.
+function textoComCodigo(destino, texto) {
+	destino.replaceChildren();
+	(texto || "").split("```").forEach((parte, i) => {
+		if (i % 2 === 1) {
+			// A primeira linha do bloco pode ser a linguagem, como em ```go.
+			const codigo = parte.replace(/^[a-z]*\n/, "").replace(/\n$/, "");
+			const pre = elemento("pre");
+			pre.append(elemento("code", codigo));
+			destino.append(pre);
+		} else if (parte.trim() !== "") {
+			destino.append(elemento("p", parte.trim()));
+		}
+	});
+}
+
+// mostrarCabecalho preenche o número, a categoria e o selo de dificuldade da
+// questão.
+function mostrarCabecalho(q) {
+	$("numero").textContent = `📝 Questão ${q.numero} de ${q.total}`;
+	$("categoria").textContent = `Categoria: ${q.categoria}`;
+	const [selo, classe] = dificuldades[q.dificuldade] || ["🔵 Normal", "normal"];
+	$("dificuldade").textContent = selo;
+	$("dificuldade").className = "selo " + classe;
+}
+
+function mostrarEnunciado(q) {
+	textoComCodigo($("enunciado"), q.questao);
+	if (q.codigo) {
+		const pre = elemento("pre");
+		pre.append(elemento("code", q.codigo));
+		$("enunciado").append(pre);
+	}
+}
+
+// montarOpcoes mostra as opções da questão, ou o campo da resposta digitada,
+// e chama responder com o pedido de resposta da API.
+function montarOpcoes(q, responder) {
+	const opcoes = $("opcoes");
+	opcoes.replaceChildren();
+	if (q.resposta_digitada) {
+		const entrada = elemento("input");
+		entrada.placeholder = q.tipo === "saida_codigo" ? "O que o programa imprime?" : "Sua resposta";
+		const enviar = elemento("button", "Responder");
+		enviar.onclick = () => responder({ resposta: entrada.value });
+		entrada.onkeydown = (e) => { if (e.key === "Enter") enviar.click(); };
+		opcoes.append(entrada, enviar);
+		entrada.focus();
+	} else if (q.tipo === "multiplas_respostas") {
+		opcoes.append(elemento("p", "Marque todas as opções corretas:", "discreto"));
+		for (const texto of q.opcoes) {
+			const rotulo = elemento("label", undefined, "opcao");
+			const marca = elemento("input");
+			marca.type = "checkbox";
+			marca.value = texto;
+			rotulo.dataset.opcao = texto;
+			rotulo.append(marca, texto);
+			opcoes.append(rotulo);
+		}
+		const enviar = elemento("button", "Responder");
+		enviar.onclick = () => {
+			const marcadas = [...opcoes.querySelectorAll("input:checked")].map((m) => m.value);
+			responder({ escolhidas: marcadas });
+		};
+		opcoes.append(enviar);
+	} else {
+		for (const texto of q.opcoes) {
+			const botao = elemento("button", texto, "opcao");
+			botao.dataset.opcao = texto;
+			botao.onclick = () => responder({ escolhidas: [texto] });
+			opcoes.append(botao);
+		}
+	}
+}
+
+function desativarOpcoes() {
+	for (const el of $("opcoes").querySelectorAll("button, input")) {
+		el.disabled = true;
+	}
+}
+
+// marcarOpcoes destaca as opções corretas e as escolhidas que estavam erradas.
+function marcarOpcoes(opcoesCorretas, escolhidas) {
+	const corretas = new Set(opcoesCorretas || []);
+	for (const el of $("opcoes").querySelectorAll(".opcao")) {
+		if (corretas.has(el.dataset.opcao)) {
+			el.classList.add("certa");
+		} else if (escolhidas.includes(el.dataset.opcao)) {
+			el.classList.add("errada");
+		}
+	}
+}
@@ -0,0 +1,282 @@
+// Página das salas de partida em grupo: abre uma sala como anfitrião ou entra
+// numa como jogador e segue a partida pelo WebSocket em
+// /api/salas/{codigo}/ws. Usa as funções de comum.js.
+"use strict";
+
+const estado = {
+	ws: null,
+	codigo: "",
+	nome: "",          // nome do jogador; vazio para o anfitrião
+	anfitriao: false,
+	conectado: false,  // a conexão chegou a abrir
+	fim: false,        // a partida terminou
+	escolhidas: [],    // opções marcadas na questão aberta
+	respondeu: false,
+	contagem: null,    // intervalo da contagem regressiva
+};
+
+function mostrar(...secoes) {
+	for (const id of ["inicio", "espera", "questao", "placar"]) {
+		$(id).hidden = !secoes.includes(id);
+	}
+}
+
+async function carregarInicio() {
+	const codigo = new URLSearchParams(location.search).get("codigo");
+	if (codigo) {
+		$("form-entrar").codigo.value = codigo;
+		$("form-entrar").nome.focus();
+	}
+
+	const [modos, categorias, niveis] = await Promise.all([
+		api("GET", "/modos"), api("GET", "/categorias"), api("GET", "/dificuldades"),
+	]);
+	const form = $("form-criar");
+	for (const m of modos.modos.filter((m) => m.em_grupo)) {
+		const opcao = elemento("option", m.nome);
+		opcao.value = m.modo;
+		opcao.selected = m.modo === "quick";
+		form.modo.append(opcao);
+	}
+	for (const c of categorias.categorias) {
+		const opcao = elemento("option", c);
+		opcao.value = c;
+		form.categoria.append(opcao);
+	}
+	for (const d of niveis.dificuldades) {
+		const opcao = elemento("option", (dificuldades[d] || [d])[0]);
+		opcao.value = d;
+		form.dificuldade.append(opcao);
+	}
+}
+
+function entrar(evento) {
+	evento.preventDefault();
+	limparErro();
+	const form = $("form-entrar");
+	estado.nome = form.nome.value.trim();
+	conectar(form.codigo.value.trim().toUpperCase(), { nome: estado.nome });
+}
+
+async function criar(evento) {
+	evento.preventDefault();
+	limparErro();
+	const form = $("form-criar");
+	const pedido = {
+		modo: form.modo.value,
+		categoria: form.categoria.value,
+		dificuldade: form.dificuldade.value,
+	};
+	if (form.quantidade.value) {
+		pedido.quantidade = Number(form.quantidade.value);
+	}
+	if (form.tempo.value) {
+		pedido.tempo_questao_s = Number(form.tempo.value);
+	}
+	try {
+		const criada = await api("POST", "/salas", pedido);
+		estado.anfitriao = true;
+		conectar(criada.codigo, { chave: criada.chave });
+	} catch (err) {
+		mostrarErro(err);
+	}
+}
+
+function conectar(codigo, params) {
+	estado.codigo = codigo;
+	const protocolo = location.protocol === "https:" ? "wss:" : "ws:";
+	const ws = new WebSocket(`${protocolo}//${location.host}/api/salas/${encodeURIComponent(codigo)}/ws?` +
+		new URLSearchParams(params));
+	estado.ws = ws;
+
+	ws.onopen = () => {
+		estado.conectado = true;
+		$("codigo").textContent = codigo;
+		$("comecar").hidden = !estado.anfitriao;
+		$("aguardando-inicio").hidden = estado.anfitriao;
+		if (estado.anfitriao) {
+			$("convite").textContent = `Os jogadores entram em ${location.origin}/sala.html?codigo=${codigo}`;
+			$("convite").hidden = false;
+		}
+		mostrar("espera");
+	};
+	ws.onmessage = (m) => tratar(JSON.parse(m.data));
+	ws.onclose = (e) => {
+		pararContagem();
+		if (estado.fim || e.code === 1000) {
+			return;
+		}
+		// O navegador não mostra a resposta do servidor quando a conexão é
+		// recusada, então o aviso cobre os motivos possíveis.
+		mostrarErro(new Error(estado.conectado
+			? "A conexão com a sala caiu."
+			: "Não foi possível entrar na sala: confira o código ou escolha outro nome."));
+		if (!estado.conectado) {
+			mostrar("inicio");
+		}
+	};
+}
+
+function enviar(mensagem) {
+	estado.ws.send(JSON.stringify(mensagem));
+}
+
+function tratar(ev) {
+	switch (ev.tipo) {
+	case "erro":
+		mostrarErro(new Error(ev.erro));
+		break;
+	case "espera":
+		mostrarEspera(ev.jogadores || []);
+		break;
+	case "questao":
+		limparErro();
+		mostrarQuestao(ev.questao);
+		break;
+	case "respostas":
+		if (estado.anfitriao) {
+			$("situacao").textContent = `📨 ${ev.respondidas || 0} de ${ev.conectados || 0} jogadores responderam`;
+		}
+		break;
+	case "correcao":
+		mostrarCorrecao(ev.questao, ev.correcao);
+		mostrarPlacar(ev.placar || [], false);
+		mostrar("questao", "placar");
+		break;
+	case "fim":
+		estado.fim = true;
+		mostrarPlacar(ev.placar || [], true);
+		mostrar("placar");
+		break;
+	}
+}
+
+function mostrarEspera(jogadores) {
+	$("contagem-jogadores").textContent = jogadores.length === 0
+		? "👥 Ninguém na sala ainda."
+		: `👥 Na sala (${jogadores.length})`;
+	$("jogadores").replaceChildren(...jogadores.map((nome) => elemento("li", nome)));
+	$("comecar").disabled = jogadores.length === 0;
+}
+
+function mostrarQuestao(q) {
+	estado.escolhidas = [];
+	estado.respondeu = false;
+
+	mostrarCabecalho(q);
+	mostrarEnunciado(q);
+	montarOpcoes(q, responder);
+	if (estado.anfitriao) {
+		desativarOpcoes();
+		$("situacao").textContent = "📨 Aguardando as respostas...";
+	} else {
+		$("situacao").textContent = "";
+	}
+
+	$("correcao").hidden = true;
+	iniciarContagem(q.prazo);
+	mostrar("questao");
+}
+
+// iniciarContagem mostra o tempo restante da questão. Quem não respondeu a
+// tempo fica sem pontos; o servidor fecha a questão sozinho.
+function iniciarContagem(prazo) {
+	pararContagem();
+	if (!prazo) {
+		return;
+	}
+	const fim = new Date(prazo).getTime();
+	const atualizar = () => {
+		const restante = Math.max(0, Math.ceil((fim - Date.now()) / 1000));
+		$("contagem").textContent = `⏰ ${restante}s`;
+		if (restante === 0) {
+			pararContagem();
+			desativarOpcoes();
+			if (!estado.anfitriao && !estado.respondeu) {
+				$("situacao").textContent = "⏰ Tempo esgotado!";
+			}
+		}
+	};
+	$("contagem").hidden = false;
+	atualizar();
+	estado.contagem = setInterval(atualizar, 250);
+}
+
+function pararContagem() {
+	clearInterval(estado.contagem);
+	estado.contagem = null;
+	$("contagem").hidden = true;
+}
+
+function responder(pedido) {
+	if (estado.respondeu) {
+		return;
+	}
+	estado.respondeu = true;
+	estado.escolhidas = pedido.escolhidas || [];
+	desativarOpcoes();
+	enviar({ tipo: "responder", ...pedido });
+	$("situacao").textContent = "📨 Resposta enviada! Aguardando os outros jogadores...";
+}
+
+function mostrarCorrecao(q, c) {
+	pararContagem();
+	desativarOpcoes();
+	$("situacao").textContent = "";
+
+	const veredito = $("veredito");
+	if (estado.anfitriao) {
+		veredito.textContent = `✅ A resposta correta é: ${c.resposta_correta}`;
+		veredito.className = "certa";
+	} else if (!c.respondeu) {
+		veredito.textContent = `⏰ Tempo esgotado! A resposta correta é: ${c.resposta_correta}`;
+		veredito.className = "esgotado";
+	} else if (c.correta) {
+		veredito.textContent = `✅ Resposta correta! Você ganhou ${c.pontos} pontos!`;
+		veredito.className = "certa";
+	} else {
+		veredito.textContent = `❌ Resposta incorreta! A resposta correta é: ${c.resposta_correta}`;
+		veredito.className = "errada";
+	}
+	textoComCodigo($("explicacao"), "💡 " + c.explicacao);
+	marcarOpcoes(c.opcoes_corretas, estado.escolhidas);
+	$("correcao").hidden = false;
+
+	$("proxima-questao").textContent = q.numero < q.total ? "⏳ A próxima questão já vem..." : "";
+}
+
+function mostrarPlacar(placar, final) {
+	$("titulo-placar").textContent = final ? "🏆 Placar final 🏆" : "📊 Placar";
+	if (final) {
+		$("proxima-questao").textContent = "";
+	}
+	$("nova-partida").hidden = !final;
+
+	const medalhas = ["🥇", "🥈", "🥉"];
+	const cabecalho = elemento("tr");
+	for (const titulo of ["", "Jogador", "Pontos", "Acertos"]) {
+		cabecalho.append(elemento("th", titulo));
+	}
+	$("posicoes").replaceChildren(cabecalho, ...placar.map((p, i) => {
+		const linha = elemento("tr");
+		if (p.nome === estado.nome) {
+			linha.className = "eu";
+		}
+		const pontos = elemento("td", p.pontos);
+		if (!final && p.rodada > 0) {
+			pontos.append(elemento("span", ` (+${p.rodada})`, "rodada"));
+		}
+		const nome = elemento("td", p.nome);
+		if (!p.conectado) {
+			nome.append(elemento("span", " (saiu)", "saiu"));
+		}
+		linha.append(elemento("td", medalhas[i] || `${i + 1}.`), nome, pontos, elemento("td", p.acertos));
+		return linha;
+	}));
+}
+
+$("form-entrar").onsubmit = entrar;
+$("form-criar").onsubmit = criar;
+$("comecar").onclick = () => enviar({ tipo: "iniciar" });
+
+carregarInicio().catch(mostrarErro);
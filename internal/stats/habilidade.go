@@ -0,0 +1,66 @@
+package stats
+
+import "math"
+
+// A habilidade do jogador é um rating no estilo Elo: cada resposta é uma
+// "partida" contra a questão, cujo rating depende da dificuldade.
+const (
+	HabilidadeInicial = 1000.0
+
+	// fatorK controla quanto uma resposta move o rating.
+	fatorK = 32.0
+)
+
+// ratingDificuldade é o rating atribuído às questões de cada dificuldade.
+var ratingDificuldade = map[string]float64{
+	"facil":   800,
+	"medio":   1000,
+	"dificil": 1200,
+}
+
+// niveis lista as dificuldades da mais fácil para a mais difícil.
+var niveis = []string{"facil", "medio", "dificil"}
+
+// ChanceAcerto é a probabilidade esperada de um jogador com a habilidade
+// informada acertar uma questão da dificuldade.
+func ChanceAcerto(habilidade float64, dificuldade string) float64 {
+	rating, ok := ratingDificuldade[dificuldade]
+	if !ok {
+		rating = ratingDificuldade["medio"]
+	}
+	return 1 / (1 + math.Pow(10, (rating-habilidade)/400))
+}
+
+// AtualizarHabilidade retorna a habilidade depois de uma resposta.
+func AtualizarHabilidade(habilidade float64, dificuldade string, correta bool) float64 {
+	resultado := 0.0
+	if correta {
+		resultado = 1
+	}
+	return habilidade + fatorK*(resultado-ChanceAcerto(habilidade, dificuldade))
+}
+
+// NivelHabilidade retorna a dificuldade cujo rating é o mais próximo da
+// habilidade, ou seja, a que o jogador acerta perto de metade das vezes.
+func NivelHabilidade(habilidade float64) string {
+	melhor := niveis[0]
+	for _, nivel := range niveis[1:] {
+		if math.Abs(ratingDificuldade[nivel]-habilidade) < math.Abs(ratingDificuldade[melhor]-habilidade) {
+			melhor = nivel
+		}
+	}
+	return melhor
+}
+
+// AjustarNivel sobe ou desce passos níveis a partir de dificuldade, sem
+// passar do mais fácil nem do mais difícil.
+func AjustarNivel(dificuldade string, passos int) string {
+	i := 0
+	for j, nivel := range niveis {
+		if nivel == dificuldade {
+			i = j
+		}
+	}
+	i = max(0, min(len(niveis)-1, i+passos))
+	return niveis[i]
+}
@@ -0,0 +1,50 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAtualizarHabilidade(t *testing.T) {
+	casos := []struct {
+		habilidade  float64
+		dificuldade string
+		correta     bool
+		esperada    float64
+	}{
+		{1000, "medio", true, 1016},
+		{1000, "medio", false, 984},
+		{1000, "facil", true, 1007.69}, // acertar a fácil rende pouco
+		{1000, "facil", false, 975.69}, // e errá-la custa muito
+		{1000, "dificil", true, 1024.31},
+		{1000, "dificil", false, 992.31},
+		{1000, "desconhecida", true, 1016}, // tratada como média
+		{1200, "dificil", false, 1184},
+	}
+	for _, c := range casos {
+		got := AtualizarHabilidade(c.habilidade, c.dificuldade, c.correta)
+		if math.Abs(got-c.esperada) > 0.01 {
+			t.Errorf("AtualizarHabilidade(%v, %s, %v) = %.2f, esperado %.2f", c.habilidade, c.dificuldade, c.correta, got, c.esperada)
+		}
+		if subiu := got > c.habilidade; subiu != c.correta {
+			t.Errorf("AtualizarHabilidade(%v, %s, %v) = %.2f: o rating deveria subir com o acerto e descer com o erro", c.habilidade, c.dificuldade, c.correta, got)
+		}
+	}
+}
+
+func TestChanceAcerto(t *testing.T) {
+	if p := ChanceAcerto(1000, "medio"); p != 0.5 {
+		t.Errorf("chance contra uma questão do mesmo nível = %v, esperado 0.5", p)
+	}
+	if facil, dificil := ChanceAcerto(1000, "facil"), ChanceAcerto(1000, "dificil"); facil <= 0.5 || dificil >= 0.5 {
+		t.Errorf("chances: fácil %.2f, difícil %.2f", facil, dificil)
+	}
+}
+
+func TestNivelHabilidade(t *testing.T) {
+	for habilidade, nivel := range map[float64]string{600: "facil", 880: "facil", 920: "medio", 1090: "medio", 1110: "dificil", 1500: "dificil"} {
+		if got := NivelHabilidade(habilidade); got != nivel {
+			t.Errorf("NivelHabilidade(%v) = %s, esperado %s", habilidade, got, nivel)
+		}
+	}
+}
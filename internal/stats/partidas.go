@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"cmp"
+	"time"
+)
+
+// Partida resume um quiz terminado, para os quadros de recordes.
+type Partida struct {
+	Sessao      string    `json:"sessao"`
+	Modo        string    `json:"modo"`
+	Dificuldade string    `json:"dificuldade,omitempty"` // vazio: todas
+	Acertos     int       `json:"acertos"`
+	Respondidas int       `json:"respondidas"`
+	DuracaoMs   int64     `json:"duracao_ms"` // soma do tempo das respostas
+	Sequencia   int       `json:"sequencia"`  // maior sequência de acertos
+	Momento     time.Time `json:"momento"`    // fim do quiz
+}
+
+// NovaPartida resume as respostas de um quiz terminado com respondidas
+// questões, que incluem as que ficaram sem resposta no fim da prova. O tempo
+// é o das respostas, sem as pausas entre as questões.
+func NovaPartida(sessao, modo, dificuldade string, respondidas int, respostas []Resposta, fim time.Time) Partida {
+	p := Partida{Sessao: sessao, Modo: modo, Dificuldade: dificuldade, Respondidas: respondidas, Momento: fim}
+	seguidos := 0
+	for _, r := range respostas {
+		p.DuracaoMs += r.DuracaoMs
+		if !r.Correta {
+			seguidos = 0
+			continue
+		}
+		p.Acertos++
+		seguidos++
+		p.Sequencia = max(p.Sequencia, seguidos)
+	}
+	return p
+}
+
+func (p Partida) Percentual() float64 {
+	if p.Respondidas == 0 {
+		return 0
+	}
+	return float64(p.Acertos) / float64(p.Respondidas) * 100
+}
+
+func (p Partida) Duracao() time.Duration {
+	return time.Duration(p.DuracaoMs) * time.Millisecond
+}
+
+// TempoMedio retorna o tempo médio por questão, que compara partidas de
+// tamanhos diferentes no mesmo quadro.
+func (p Partida) TempoMedio() time.Duration {
+	if p.Respondidas == 0 {
+		return 0
+	}
+	return p.Duracao() / time.Duration(p.Respondidas)
+}
+
+// CompararPartidas ordena as partidas da melhor para a pior: maior
+// percentual de acertos, depois menor tempo médio por questão, depois maior
+// sequência de acertos. No empate, a mais antiga fica à frente.
+func CompararPartidas(a, b Partida) int {
+	if c := cmp.Compare(b.Percentual(), a.Percentual()); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.TempoMedio(), b.TempoMedio()); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(b.Sequencia, a.Sequencia); c != 0 {
+		return c
+	}
+	return a.Momento.Compare(b.Momento)
+}
@@ -0,0 +1,92 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// EstadoRevisao é o agendamento de revisão de uma questão, no estilo do
+// algoritmo SM-2: cada acerto multiplica o intervalo pela facilidade e cada
+// erro traz a questão de volta no dia seguinte.
+type EstadoRevisao struct {
+	Facilidade float64   `json:"facilidade"`
+	Intervalo  int       `json:"intervalo_dias"`
+	Repeticoes int       `json:"repeticoes"` // acertos seguidos
+	Proxima    time.Time `json:"proxima"`
+	Ultima     time.Time `json:"ultima"`
+}
+
+const (
+	facilidadeInicial = 2.5
+	facilidadeMinima  = 1.3
+
+	// Respostas certas mais rápidas que isso contam como "fácil" (nota 5).
+	respostaRapida = 15 * time.Second
+)
+
+// nota converte uma resposta na escala de 0 a 5 do SM-2. Deixar o tempo
+// acabar é tratado como não lembrar a resposta.
+func nota(r Resposta) int {
+	switch {
+	case r.TempoEsgotado:
+		return 0
+	case !r.Correta:
+		return 1
+	case r.Duracao() < respostaRapida:
+		return 5
+	default:
+		return 4
+	}
+}
+
+func (e EstadoRevisao) revisar(nota int, momento time.Time) EstadoRevisao {
+	if e.Facilidade == 0 {
+		e.Facilidade = facilidadeInicial
+	}
+
+	if nota < 3 {
+		e.Repeticoes = 0
+		e.Intervalo = 1
+	} else {
+		e.Repeticoes++
+		switch e.Repeticoes {
+		case 1:
+			e.Intervalo = 1
+		case 2:
+			e.Intervalo = 6
+		default:
+			e.Intervalo = int(math.Round(float64(e.Intervalo) * e.Facilidade))
+		}
+	}
+
+	n := float64(5 - nota)
+	e.Facilidade = math.Max(facilidadeMinima, e.Facilidade+0.1-n*(0.08+n*0.02))
+
+	e.Ultima = momento
+	dia := momento.Local()
+	e.Proxima = time.Date(dia.Year(), dia.Month(), dia.Day()+e.Intervalo, 0, 0, 0, 0, time.Local)
+	return e
+}
+
+// ParaRevisar retorna os IDs das questões com revisão vencida até o fim do
+// dia de agora, das mais atrasadas para as mais recentes.
+func (s Estatisticas) ParaRevisar(agora time.Time) []int {
+	dia := agora.Local()
+	fimDoDia := time.Date(dia.Year(), dia.Month(), dia.Day()+1, 0, 0, 0, 0, time.Local)
+
+	var ids []int
+	for id, estado := range s.Revisoes {
+		if estado.Proxima.Before(fimDoDia) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := s.Revisoes[ids[i]], s.Revisoes[ids[j]]
+		if !a.Proxima.Equal(b.Proxima) {
+			return a.Proxima.Before(b.Proxima)
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
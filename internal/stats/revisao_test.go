@@ -0,0 +1,53 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRevisarIntervalos(t *testing.T) {
+	casos := []struct {
+		nome       string
+		notas      []int
+		intervalos []int // depois de cada revisão
+	}{
+		{"acertos lembrando com esforço", []int{4, 4, 4, 4}, []int{1, 6, 15, 38}},
+		{"acertos rápidos", []int{5, 5, 5, 5}, []int{1, 6, 16, 45}},
+		{"erro recomeça a sequência", []int{4, 4, 4, 1, 4, 4}, []int{1, 6, 15, 1, 1, 6}},
+		{"tempo esgotado", []int{0, 0}, []int{1, 1}},
+	}
+	inicio := time.Date(2026, 3, 2, 15, 30, 0, 0, time.Local)
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			var e EstadoRevisao
+			for i, n := range c.notas {
+				e = e.revisar(n, inicio)
+				if e.Intervalo != c.intervalos[i] {
+					t.Fatalf("revisão %d (nota %d): intervalo %d, esperado %d", i+1, n, e.Intervalo, c.intervalos[i])
+				}
+			}
+			if e.Facilidade < facilidadeMinima {
+				t.Errorf("facilidade %.2f abaixo do mínimo", e.Facilidade)
+			}
+			esperada := time.Date(2026, 3, 2+e.Intervalo, 0, 0, 0, 0, time.Local)
+			if !e.Proxima.Equal(esperada) {
+				t.Errorf("próxima revisão em %v, esperado %v", e.Proxima, esperada)
+			}
+		})
+	}
+}
+
+func TestRevisarFacilidade(t *testing.T) {
+	var e EstadoRevisao
+	e = e.revisar(5, time.Now())
+	if e.Facilidade <= facilidadeInicial {
+		t.Errorf("um acerto rápido deveria aumentar a facilidade: %.2f", e.Facilidade)
+	}
+	for range 10 {
+		e = e.revisar(1, time.Now())
+	}
+	if e.Facilidade != facilidadeMinima || e.Repeticoes != 0 {
+		t.Errorf("depois de vários erros: facilidade %.2f, repetições %d", e.Facilidade, e.Repeticoes)
+	}
+}
@@ -1,33 +1,267 @@
 package stats
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"os"
+	"slices"
+	"sort"
+	"time"
 )
 
+// VersaoAtual é a versão do formato de quiz_stats.json. Arquivos sem o
+// campo "versao" são da versão 0, que só tinha os totais; a versão 1 não
+// tinha o agendamento de revisões e a 2 não tinha a habilidade.
+const VersaoAtual = 3
+
+// FormatoUltimoQuiz é o formato da data em UltimoQuiz.
+const FormatoUltimoQuiz = "02/01/2006 15:04"
+
 type Estatisticas struct {
-	TotalQuizzes    int     `json:"total_quizzes"`
-	TotalAcertos    int     `json:"total_acertos"`
-	TotalQuestoes   int     `json:"total_questoes"`
-	MelhorScore     int     `json:"melhor_score"`
-	MediaPercentual float64 `json:"media_percentual"`
-	UltimoQuiz      string  `json:"ultimo_quiz"`
+	Versao          int        `json:"versao"`
+	TotalQuizzes    int        `json:"total_quizzes"`
+	TotalAcertos    int        `json:"total_acertos"`
+	TotalQuestoes   int        `json:"total_questoes"`
+	MelhorScore     int        `json:"melhor_score"`
+	MediaPercentual float64    `json:"media_percentual"`
+	UltimoQuiz      string     `json:"ultimo_quiz"`
+	Respostas       []Resposta `json:"respostas"`
+
+	// Revisoes guarda o agendamento de revisão de cada questão respondida.
+	Revisoes map[int]EstadoRevisao `json:"revisoes"`
+
+	// Habilidade é o rating estimado do jogador. Veja AtualizarHabilidade.
+	Habilidade float64 `json:"habilidade"`
+
+	// Partidas resume os quizzes terminados, para os recordes.
+	Partidas []Partida `json:"partidas,omitempty"`
+}
+
+// Resposta registra uma questão respondida.
+type Resposta struct {
+	QuestaoID   int       `json:"questao_id"`
+	Categoria   string    `json:"categoria"`
+	Dificuldade string    `json:"dificuldade"`
+	Escolhida   string    `json:"escolhida"`
+	Correta     bool      `json:"correta"`
+	DuracaoMs   int64     `json:"duracao_ms"`
+	Momento     time.Time `json:"momento"`
+	Sessao      string    `json:"sessao"`
+
+	// TempoEsgotado indica que o tempo da questão acabou antes da resposta.
+	// A questão conta como não acertada, mas fica separada das erradas.
+	TempoEsgotado bool `json:"tempo_esgotado,omitempty"`
 }
 
+func (r Resposta) Duracao() time.Duration {
+	return time.Duration(r.DuracaoMs) * time.Millisecond
+}
+
+// CarregarEstatisticas lê as estatísticas de statsFile. Um arquivo que não
+// é JSON válido, como um truncado, retorna erro em vez de estatísticas
+// zeradas.
 func CarregarEstatisticas(statsFile string) (Estatisticas, error) {
 	var stats Estatisticas
 	data, err := os.ReadFile(statsFile)
 	if err != nil {
 		return stats, err
 	}
-	json.Unmarshal(data, &stats)
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return Estatisticas{}, fmt.Errorf("%s: estatísticas inválidas: %v", statsFile, err)
+	}
+	migrar(&stats)
 	return stats, nil
 }
 
+// migrar atualiza estatísticas de versões anteriores. Da versão 0 ficam os
+// totais, que usam os mesmos campos; o histórico começa vazio, pois ela não
+// guardava as respostas. Nas versões 1 e 2, as revisões e a habilidade são
+// calculadas repassando o histórico.
+func migrar(stats *Estatisticas) {
+	if stats.Versao < 3 {
+		respostas := stats.Respostas
+		stats.Respostas = nil
+		stats.Revisoes = nil
+		stats.Habilidade = 0
+		stats.Registrar(respostas...)
+	}
+	stats.Versao = VersaoAtual
+}
+
+// Registrar adiciona as respostas ao histórico, reagenda a revisão de cada
+// questão respondida e atualiza a habilidade do jogador.
+func (s *Estatisticas) Registrar(respostas ...Resposta) {
+	if s.Revisoes == nil {
+		s.Revisoes = make(map[int]EstadoRevisao)
+	}
+	for _, r := range respostas {
+		s.Respostas = append(s.Respostas, r)
+		s.Revisoes[r.QuestaoID] = s.Revisoes[r.QuestaoID].revisar(nota(r), r.Momento)
+		s.Habilidade = AtualizarHabilidade(s.HabilidadeAtual(), r.Dificuldade, r.Correta)
+	}
+}
+
+// HabilidadeAtual retorna a habilidade estimada, ou a inicial se o jogador
+// ainda não respondeu nada.
+func (s Estatisticas) HabilidadeAtual() float64 {
+	if s.Habilidade == 0 {
+		return HabilidadeInicial
+	}
+	return s.Habilidade
+}
+
 func SalvarEstatisticas(statsFile string, stats Estatisticas) error {
+	stats.Versao = VersaoAtual
 	data, err := json.MarshalIndent(stats, "", "  ")
 	if err != nil {
 		return err
 	}
 	return os.WriteFile(statsFile, data, 0644)
 }
+
+// Mesclar junta as estatísticas de dois jogadores, como ao unir dois perfis.
+// Os totais são somados, as respostas e partidas repetidas ficam uma vez só,
+// e as revisões e a habilidade são recalculadas repassando o histórico em
+// ordem.
+func Mesclar(a, b Estatisticas) Estatisticas {
+	m := Estatisticas{
+		TotalQuizzes:  a.TotalQuizzes + b.TotalQuizzes,
+		TotalAcertos:  a.TotalAcertos + b.TotalAcertos,
+		TotalQuestoes: a.TotalQuestoes + b.TotalQuestoes,
+		MelhorScore:   max(a.MelhorScore, b.MelhorScore),
+		UltimoQuiz:    a.UltimoQuiz,
+	}
+	if m.TotalQuestoes > 0 {
+		m.MediaPercentual = float64(m.TotalAcertos) / float64(m.TotalQuestoes) * 100
+	}
+	if depois(b.UltimoQuiz, a.UltimoQuiz) {
+		m.UltimoQuiz = b.UltimoQuiz
+	}
+
+	respostas := append(slices.Clone(a.Respostas), b.Respostas...)
+	slices.SortStableFunc(respostas, func(x, y Resposta) int { return x.Momento.Compare(y.Momento) })
+	respostas = slices.CompactFunc(respostas, func(x, y Resposta) bool {
+		return x.Sessao == y.Sessao && x.QuestaoID == y.QuestaoID && x.Momento.Equal(y.Momento)
+	})
+	m.Registrar(respostas...)
+
+	m.Partidas = append(slices.Clone(a.Partidas), b.Partidas...)
+	slices.SortStableFunc(m.Partidas, func(x, y Partida) int { return x.Momento.Compare(y.Momento) })
+	m.Partidas = slices.CompactFunc(m.Partidas, func(x, y Partida) bool { return x.Sessao == y.Sessao })
+	m.Versao = VersaoAtual
+	return m
+}
+
+// depois informa se a data de UltimoQuiz x é posterior a y.
+func depois(x, y string) bool {
+	tx, errX := time.ParseInLocation(FormatoUltimoQuiz, x, time.Local)
+	ty, errY := time.ParseInLocation(FormatoUltimoQuiz, y, time.Local)
+	if errX != nil {
+		return false
+	}
+	return errY != nil || tx.After(ty)
+}
+
+// NovaSessao gera o identificador que agrupa as respostas de um quiz.
+func NovaSessao() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Desempenho resume um grupo de respostas.
+type Desempenho struct {
+	Nome        string
+	Respondidas int
+	Acertos     int
+	SemTempo    int // respondidas em que o tempo acabou
+	TempoTotal  time.Duration
+}
+
+func (d Desempenho) Percentual() float64 {
+	if d.Respondidas == 0 {
+		return 0
+	}
+	return float64(d.Acertos) / float64(d.Respondidas) * 100
+}
+
+func (d Desempenho) TempoMedio() time.Duration {
+	if d.Respondidas == 0 {
+		return 0
+	}
+	return d.TempoTotal / time.Duration(d.Respondidas)
+}
+
+func (d *Desempenho) somar(r Resposta) {
+	d.Respondidas++
+	if r.Correta {
+		d.Acertos++
+	}
+	if r.TempoEsgotado {
+		d.SemTempo++
+	}
+	d.TempoTotal += r.Duracao()
+}
+
+// agrupar soma as respostas pela chave, ordenando os grupos pelo nome.
+func agrupar(respostas []Resposta, chave func(Resposta) string) []Desempenho {
+	grupos := make(map[string]*Desempenho)
+	for _, r := range respostas {
+		nome := chave(r)
+		d, ok := grupos[nome]
+		if !ok {
+			d = &Desempenho{Nome: nome}
+			grupos[nome] = d
+		}
+		d.somar(r)
+	}
+
+	resultado := make([]Desempenho, 0, len(grupos))
+	for _, d := range grupos {
+		resultado = append(resultado, *d)
+	}
+	sort.Slice(resultado, func(i, j int) bool { return resultado[i].Nome < resultado[j].Nome })
+	return resultado
+}
+
+// PorCategoria retorna o desempenho em cada categoria respondida.
+func (s Estatisticas) PorCategoria() []Desempenho {
+	return agrupar(s.Respostas, func(r Resposta) string { return r.Categoria })
+}
+
+// PorDificuldade retorna o desempenho em cada dificuldade respondida.
+func (s Estatisticas) PorDificuldade() []Desempenho {
+	return agrupar(s.Respostas, func(r Resposta) string { return r.Dificuldade })
+}
+
+// Periodo é o desempenho numa semana, começando na segunda-feira Inicio.
+type Periodo struct {
+	Inicio time.Time
+	Desempenho
+}
+
+// Tendencia retorna o desempenho das últimas semanas com respostas, da mais
+// antiga para a mais recente, com no máximo semanas itens.
+func (s Estatisticas) Tendencia(semanas int) []Periodo {
+	grupos := agrupar(s.Respostas, func(r Resposta) string {
+		return inicioSemana(r.Momento).Format("2006-01-02")
+	})
+	if len(grupos) > semanas {
+		grupos = grupos[len(grupos)-semanas:]
+	}
+
+	periodos := make([]Periodo, len(grupos))
+	for i, d := range grupos {
+		inicio, _ := time.ParseInLocation("2006-01-02", d.Nome, time.Local)
+		periodos[i] = Periodo{Inicio: inicio, Desempenho: d}
+	}
+	return periodos
+}
+
+func inicioSemana(t time.Time) time.Time {
+	t = t.Local()
+	dias := (int(t.Weekday()) + 6) % 7 // segunda-feira = 0
+	return time.Date(t.Year(), t.Month(), t.Day()-dias, 0, 0, 0, 0, time.Local)
+}
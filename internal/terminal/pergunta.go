@@ -0,0 +1,105 @@
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/pterm/pterm"
+
+	"quiz_go/internal/quiz"
+	"quiz_go/internal/ui"
+)
+
+// perguntarQuestao mostra a questão no prompt do seu tipo e retorna as
+// opções marcadas ou o texto digitado. Veja perguntar.
+func perguntarQuestao(questao quiz.Questao, prazo time.Time) (escolhidas []string, esgotado bool, err error) {
+	enunciado, _ := separarEnunciado(questao)
+	mensagem := ui.Bold(enunciado)
+	switch {
+	case questao.TipoQuestao() == quiz.TipoMultiplasRespostas:
+		prompt := &survey.MultiSelect{
+			Message: mensagem + " (marque todas as corretas)",
+			Options: questao.Opcoes,
+		}
+		esgotado, err = perguntar(prompt, &escolhidas, prazo, &prompt.Message)
+		return escolhidas, esgotado, err
+
+	case questao.RespostaDigitada():
+		// O survey não redesenha o campo de texto sozinho, então o tempo
+		// disponível é mostrado uma vez só.
+		prompt := &survey.Input{Message: mensagem}
+		if !prazo.IsZero() {
+			prompt.Message += ui.Yellow(fmt.Sprintf(" (⏰ responda em até %s)", time.Until(prazo).Round(time.Second)))
+		}
+		var resposta string
+		esgotado, err = perguntar(prompt, &resposta, prazo, nil)
+		return []string{resposta}, esgotado, err
+
+	default:
+		prompt := &survey.Select{
+			Message: mensagem,
+			Options: questao.OpcoesExibidas(),
+		}
+		var resposta string
+		esgotado, err = perguntar(prompt, &resposta, prazo, &prompt.Message)
+		return []string{resposta}, esgotado, err
+	}
+}
+
+// separarEnunciado tira do texto da questão os blocos de código cercados
+// por ```, que o survey não mostra bem na mensagem da pergunta. Os códigos
+// voltam na ordem em que são mostrados: o campo Codigo e depois os blocos.
+func separarEnunciado(questao quiz.Questao) (texto string, codigos []string) {
+	if strings.TrimSpace(questao.Codigo) != "" {
+		codigos = append(codigos, questao.Codigo)
+	}
+	var partes []string
+	for _, bloco := range ui.SepararBlocos(questao.Questao) {
+		if bloco.Codigo {
+			codigos = append(codigos, bloco.Texto)
+		} else {
+			partes = append(partes, strings.TrimSpace(bloco.Texto))
+		}
+	}
+	return strings.Join(partes, " "), codigos
+}
+
+// perguntar mostra uma questão e espera a resposta até o prazo, com uma
+// contagem regressiva na própria pergunta. Sem prazo, espera o quanto for
+// preciso. esgotado indica que o prazo interrompeu a pergunta; respostas
+// que chegam atrasadas são tratadas pela quiz.Sessao.
+func perguntar(prompt survey.Prompt, resposta any, prazo time.Time, contagem *string) (esgotado bool, err error) {
+	if prazo.IsZero() {
+		return false, survey.AskOne(prompt, resposta)
+	}
+
+	entrada := &ui.EntradaComPrazo{Prazo: prazo}
+	if contagem != nil {
+		mensagem := *contagem
+		entrada.AoTique = func(restante time.Duration) {
+			*contagem = mensagem + " " + textoContagem(restante)
+		}
+		entrada.AoTique(time.Until(prazo))
+	}
+
+	err = survey.AskOne(prompt, resposta, survey.WithStdio(entrada, os.Stdout, os.Stderr))
+	if errors.Is(err, terminal.InterruptErr) && !time.Now().Before(prazo) {
+		return true, nil
+	}
+	return false, err
+}
+
+// textoContagem formata o tempo restante, em vermelho nos últimos segundos.
+func textoContagem(restante time.Duration) string {
+	segundos := int((restante + time.Second - 1) / time.Second)
+	texto := fmt.Sprintf("⏰ %d:%02d", segundos/60, segundos%60)
+	if segundos <= 10 {
+		return pterm.NewStyle(pterm.FgRed, pterm.Bold).Sprint(texto)
+	}
+	return pterm.NewStyle(pterm.FgYellow).Sprint(texto)
+}
@@ -0,0 +1,55 @@
+package terminal
+
+import (
+	"fmt"
+
+	"quiz_go/internal/quiz"
+	"quiz_go/internal/stats"
+	"quiz_go/internal/ui"
+)
+
+// MostrarRecordes mostra os quadros de recordes, um por modo e dificuldade.
+func MostrarRecordes(quadros []quiz.Quadro) {
+	if len(quadros) == 0 {
+		fmt.Println(ui.Yellow("🏅 Nenhum recorde ainda. Termine um quiz para entrar nos quadros."))
+		return
+	}
+
+	fmt.Println(ui.Cyan("╔══════════════════════════════════════════════════════════╗"))
+	fmt.Println(ui.Cyan("║") + "                      " + ui.Bold("🏅 RECORDES 🏅") + "                      " + ui.Cyan("║"))
+	fmt.Println(ui.Cyan("╚══════════════════════════════════════════════════════════╝"))
+
+	for _, quadro := range quadros {
+		fmt.Println()
+		fmt.Println(ui.Cyan("🏁 " + quadro.Nome()))
+		for i, r := range quadro.Registros {
+			fmt.Printf("   %s %-20s %s %5d/%-3d ⏱️ %6.1fs  🔥 %-3d %s\n",
+				posicao(i),
+				nomePerfil(r.Perfil),
+				ui.Bold(fmt.Sprintf("%5.1f%%", r.Percentual())),
+				r.Acertos, r.Respondidas,
+				r.Duracao().Seconds(),
+				r.Sequencia,
+				ui.Blue(r.Momento.Local().Format(stats.FormatoUltimoQuiz)))
+		}
+	}
+	fmt.Println()
+}
+
+// posicao retorna a medalha das três primeiras posições e o número das
+// demais.
+func posicao(i int) string {
+	if medalhas := []string{"🥇", "🥈", "🥉"}; i < len(medalhas) {
+		return medalhas[i]
+	}
+	return fmt.Sprintf("%2d", i+1)
+}
+
+// nomePerfil retorna o nome mostrado para o perfil, que fica vazio quando o
+// quiz roda sem perfis.
+func nomePerfil(nome string) string {
+	if nome == "" {
+		return "você"
+	}
+	return nome
+}
@@ -0,0 +1,139 @@
+package terminal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pterm/pterm"
+
+	"quiz_go/internal/quiz"
+	"quiz_go/internal/stats"
+	"quiz_go/internal/ui"
+)
+
+func MostrarResultados(r quiz.Resumo) {
+	fmt.Println()
+	fmt.Println(ui.Cyan("╔══════════════════════════════════════════════════════════╗"))
+	fmt.Println(ui.Cyan("║") + "                    " + ui.Bold("🏆 RESULTADOS FINAIS 🏆") + "                    " + ui.Cyan("║"))
+	fmt.Println(ui.Cyan("╚══════════════════════════════════════════════════════════╝"))
+	fmt.Println()
+
+	spinner, _ := pterm.DefaultSpinner.Start(ui.Magenta("Calculando resultados..."))
+	time.Sleep(2 * time.Second)
+	spinner.Success(pterm.Green("Cálculos finalizados!"))
+	fmt.Println()
+
+	fmt.Printf("%s Você acertou %s de %s questões\n",
+		ui.Magenta("📊"),
+		ui.Bold(ui.Green(fmt.Sprintf("%d", r.Acertos))),
+		ui.Bold(fmt.Sprintf("%d", r.Respondidas)))
+
+	fmt.Printf("%s Percentual de acertos: %s\n",
+		ui.Magenta("📈"),
+		ui.Bold(fmt.Sprintf("%.1f%%", r.Percentual())))
+
+	if semTempo := r.SemTempo(); semTempo > 0 {
+		fmt.Printf("%s Sem resposta (tempo esgotado): %s\n",
+			ui.Yellow("⏰"),
+			ui.Bold(fmt.Sprintf("%d", semTempo)))
+	}
+
+	if r.Bonus {
+		fmt.Printf("%s Pontuação com bônus de velocidade: %s\n",
+			ui.Magenta("🏅"),
+			ui.Bold(fmt.Sprintf("%.1f", r.Pontos)))
+	}
+
+	fmt.Printf("%s Tempo total: %s\n",
+		ui.Blue("⏱️"),
+		ui.Bold(fmt.Sprintf("%.1f segundos", r.Tempo.Seconds())))
+
+	fmt.Printf("%s Tempo médio por questão: %s\n",
+		ui.Blue("⚡"),
+		ui.Bold(fmt.Sprintf("%.1f segundos", r.Tempo.Seconds()/float64(max(1, r.Respondidas-r.SemResposta)))))
+
+	fmt.Println()
+	mostrarRecorde(r)
+
+	if r.ErroEstatisticas != nil {
+		fmt.Println(ui.Red("❌ Não foi possível salvar as estatísticas: " + r.ErroEstatisticas.Error()))
+		fmt.Println()
+	}
+
+	fmt.Println(ui.Cyan("📋 Resumo das suas respostas:"))
+	for i, resposta := range r.Respostas {
+		status := ui.Red("❌")
+		switch {
+		case resposta.Correta:
+			status = ui.Green("✅")
+		case resposta.TempoEsgotado:
+			status = ui.Yellow("⏰")
+		}
+		fmt.Printf("   Questão %d: %s\n", i+1, status)
+	}
+	fmt.Println()
+
+	MostrarMensagemFinal(r.Acertos, r.Respondidas, r.Percentual())
+	mostrarHabilidade(r.HabilidadeAnterior, r.Habilidade)
+}
+
+func MostrarMensagemFinal(score, total int, percentual float64) {
+	switch {
+	case score == total:
+		fmt.Println(ui.Green("🎉 PERFEITO! Você acertou todas as questões!"))
+		fmt.Println(ui.Green("🏆 Você é um verdadeiro expert em Go!"))
+		fmt.Println(ui.Green("🌟 Considerado um GoGuru!"))
+	case percentual >= 80:
+		fmt.Println(ui.Green("🌟 Excelente! Você tem um ótimo conhecimento em Go!"))
+		fmt.Println(ui.Green("👏 Continue assim!"))
+		fmt.Println(ui.Blue("🚀 Próximo nível: tente as questões difíceis!"))
+	case percentual >= 60:
+		fmt.Println(ui.Yellow("👍 Muito bem! Você está no caminho certo!"))
+		fmt.Println(ui.Yellow("📚 Continue estudando para melhorar ainda mais!"))
+		fmt.Println(ui.Blue("💡 Dica: revise os conceitos que errou!"))
+	case percentual >= 40:
+		fmt.Println(ui.Yellow("😊 Bom começo! Você já sabe algumas coisas sobre Go!"))
+		fmt.Println(ui.Yellow("💪 Com mais estudo você chegará lá!"))
+		fmt.Println(ui.Blue("📖 Recomendo focar nos fundamentos primeiro!"))
+	default:
+		fmt.Println(ui.Red("📖 Você precisa estudar mais sobre Go!"))
+		fmt.Println(ui.Red("💡 Que tal revisar a documentação oficial?"))
+		fmt.Println(ui.Yellow("🔗 Recursos recomendados:"))
+		fmt.Println(ui.Cyan("   • https://golang.org/doc/"))
+		fmt.Println(ui.Cyan("   • https://tour.golang.org/"))
+		fmt.Println(ui.Cyan("   • https://gobyexample.com/"))
+	}
+	fmt.Println()
+}
+
+// mostrarRecorde avisa quando o quiz bateu o recorde do quadro ou o do
+// próprio jogador.
+func mostrarRecorde(r quiz.Resumo) {
+	quadro := quiz.Quadro{Modo: r.Modo, Dificuldade: r.Dificuldade}.Nome()
+	switch {
+	case r.Recorde:
+		fmt.Println(ui.Bold(ui.Yellow(fmt.Sprintf("🏆 Novo recorde no modo %s!", quadro))))
+	case r.RecordePessoal:
+		fmt.Println(ui.Yellow(fmt.Sprintf("⭐ Sua melhor partida no modo %s!", quadro)))
+	default:
+		return
+	}
+	fmt.Println()
+}
+
+// mostrarHabilidade mostra a habilidade estimada e a variação no quiz.
+func mostrarHabilidade(anterior, atual float64) {
+	variacao := fmt.Sprintf("%+.0f", atual-anterior)
+	if atual >= anterior {
+		variacao = ui.Green(variacao)
+	} else {
+		variacao = ui.Red(variacao)
+	}
+
+	fmt.Printf("%s Nível estimado: %s (%s) | equivale às questões de nível %s\n",
+		ui.Cyan("🧭"),
+		ui.Bold(fmt.Sprintf("%.0f", atual)),
+		variacao,
+		ui.Bold(stats.NivelHabilidade(atual)))
+	fmt.Println()
+}
@@ -0,0 +1,182 @@
+package terminal
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/AlecAivazis/survey/v2/terminal"
+
+	"quiz_go/internal/quiz"
+	"quiz_go/internal/sala"
+	"quiz_go/internal/servidor"
+	"quiz_go/internal/ui"
+)
+
+// JogarSala joga uma partida em grupo no terminal, como o jogador nome:
+// mostra cada questão, envia a resposta e mostra a correção e o placar.
+func JogarSala(ctx context.Context, c *servidor.ClienteSala, nome string) error {
+	fmt.Println(ui.Cyan("⏳ Aguardando o anfitrião iniciar a partida..."))
+	for {
+		ev, err := proximoEvento(ctx, c)
+		if err != nil || ev == nil {
+			return err
+		}
+
+		switch ev.Tipo {
+		case sala.EventoEspera:
+			mostrarEspera(ev.Jogadores)
+
+		case sala.EventoQuestao:
+			Terminal{}.Questao(ev.Questao, ev.Numero, ev.Total)
+			escolhidas, esgotado, err := perguntarQuestao(ev.Questao, ev.Prazo)
+			switch {
+			case errors.Is(err, terminal.InterruptErr):
+				fmt.Println(ui.Yellow("Você saiu da sala."))
+				return nil
+			case err != nil:
+				return err
+			case esgotado:
+				continue
+			}
+			if err := c.Responder(ctx, escolhidas); err != nil {
+				return err
+			}
+			fmt.Println(ui.Cyan("📨 Resposta enviada! Aguardando os outros jogadores..."))
+
+		case sala.EventoCorrecao:
+			Terminal{}.Resultado(quiz.Resultado{
+				Questao:  ev.Questao,
+				Correta:  ev.Resposta != nil && ev.Resposta.Correta,
+				Esgotado: ev.Resposta == nil,
+			})
+			if ev.Resposta != nil && ev.Resposta.Correta {
+				fmt.Printf("%s Você ganhou %s pontos!\n\n", ui.Magenta("🏅"), ui.Bold(fmt.Sprintf("%d", ev.Resposta.Pontos)))
+			}
+			mostrarPlacar(ev.Placar, nome, false)
+
+		case sala.EventoFim:
+			ui.LimparTela()
+			mostrarPlacar(ev.Placar, nome, true)
+		}
+	}
+}
+
+// ApresentarSala mostra a partida ao anfitrião: quem entrou, as questões,
+// as respostas chegando e o placar. Enter inicia a partida.
+func ApresentarSala(ctx context.Context, c *servidor.ClienteSala, codigo string) error {
+	fmt.Println()
+	fmt.Printf("%s Sala aberta! Código: %s\n", ui.Green("🏠"), ui.Bold(ui.Yellow(codigo)))
+	fmt.Printf("   Os jogadores entram com %s\n", ui.Cyan("quiz_go room join -name <nome> "+codigo))
+	fmt.Printf("   ou pela página %s do servidor.\n", ui.Cyan("/sala.html"))
+	fmt.Println()
+	fmt.Println(ui.Magenta("Pressione Enter para começar a partida."))
+
+	var iniciada atomic.Bool
+	go func() {
+		leitor := bufio.NewScanner(os.Stdin)
+		for leitor.Scan() && !iniciada.Load() {
+			c.Iniciar(ctx)
+		}
+	}()
+
+	for {
+		ev, err := proximoEvento(ctx, c)
+		if err != nil || ev == nil {
+			return err
+		}
+
+		switch ev.Tipo {
+		case sala.EventoEspera:
+			mostrarEspera(ev.Jogadores)
+
+		case sala.EventoQuestao:
+			iniciada.Store(true)
+			Terminal{}.Questao(ev.Questao, ev.Numero, ev.Total)
+			enunciado, _ := separarEnunciado(ev.Questao)
+			fmt.Println(ui.Bold(enunciado))
+			for i, opcao := range ev.Questao.OpcoesExibidas() {
+				fmt.Printf("   %c) %s\n", 'A'+i, opcao)
+			}
+			fmt.Println()
+
+		case sala.EventoRespostas:
+			fmt.Printf(ui.Cyan("📨 %d de %d jogadores responderam\n"), ev.Respondidas, ev.Conectados)
+
+		case sala.EventoCorrecao:
+			fmt.Println()
+			fmt.Printf(ui.Green("✅ A resposta correta é: %s\n"), ui.Bold(ev.Questao.RespostaEsperada()))
+			fmt.Printf("%s %s\n\n", ui.Blue("💡 Explicação:"), ui.FormatarTexto(ev.Questao.Explicacao))
+			mostrarPlacar(ev.Placar, "", false)
+
+		case sala.EventoFim:
+			ui.LimparTela()
+			mostrarPlacar(ev.Placar, "", true)
+		}
+	}
+}
+
+// proximoEvento espera o próximo evento da partida, mostrando os pedidos
+// recusados. Retorna nil, sem erro, quando a partida termina ou ctx é
+// cancelado.
+func proximoEvento(ctx context.Context, c *servidor.ClienteSala) (*sala.Evento, error) {
+	for {
+		ev, err := c.Proximo(ctx)
+		var recusado *servidor.ErroSala
+		switch {
+		case errors.As(err, &recusado):
+			fmt.Println(ui.Yellow("⚠️  " + recusado.Mensagem))
+			continue
+		case errors.Is(err, io.EOF), ctx.Err() != nil:
+			return nil, nil
+		case err != nil:
+			return nil, fmt.Errorf("conexão com a sala perdida: %v", err)
+		}
+		return &ev, nil
+	}
+}
+
+func mostrarEspera(jogadores []string) {
+	if len(jogadores) == 0 {
+		fmt.Println(ui.Blue("👥 Ninguém na sala ainda."))
+		return
+	}
+	fmt.Printf("%s Na sala (%d): %s\n", ui.Blue("👥"), len(jogadores), strings.Join(jogadores, ", "))
+}
+
+// mostrarPlacar mostra o placar, com o jogador eu em destaque e, fora do
+// placar final, os pontos ganhos na última questão.
+func mostrarPlacar(placar []sala.Posicao, eu string, final bool) {
+	titulo := "📊 Placar"
+	if final {
+		titulo = "🏆 PLACAR FINAL 🏆"
+	}
+	fmt.Println(ui.Cyan("═══════════════════════════════════════════════════════════"))
+	fmt.Println(ui.Bold(titulo))
+	fmt.Println(ui.Cyan("═══════════════════════════════════════════════════════════"))
+
+	medalhas := []string{"🥇", "🥈", "🥉"}
+	for i, p := range placar {
+		posicao := fmt.Sprintf("%2d.", i+1)
+		if i < len(medalhas) {
+			posicao = medalhas[i] + " "
+		}
+		linha := fmt.Sprintf("%s %-24s %6d pontos | %d acertos", posicao, p.Nome, p.Pontos, p.Acertos)
+		if p.Nome == eu {
+			linha = ui.Bold(linha)
+		}
+		if !final && p.Rodada > 0 {
+			linha += ui.Green(fmt.Sprintf(" (+%d)", p.Rodada))
+		}
+		if !p.Conectado {
+			linha += ui.Yellow(" (saiu)")
+		}
+		fmt.Println("   " + linha)
+	}
+	fmt.Println()
+}
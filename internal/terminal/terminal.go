@@ -0,0 +1,205 @@
+// Package terminal é o frontend de terminal do quiz: menus e perguntas com
+// o survey e resultados coloridos.
+package terminal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/pterm/pterm"
+
+	"quiz_go/internal/quiz"
+	"quiz_go/internal/ui"
+)
+
+// Terminal mostra o quiz e lê as respostas no terminal. Implementa
+// quiz.Apresentador e quiz.FonteRespostas.
+type Terminal struct{}
+
+// ExecutarQuiz joga a sessão no terminal. Veja quiz.Jogar.
+func ExecutarQuiz(s *quiz.Sessao) {
+	quiz.Jogar(s, Terminal{}, Terminal{})
+}
+
+// SelecionarModoJogo mostra o menu de modos de jogo e retorna a opção escolhida.
+func SelecionarModoJogo(q *quiz.Quiz) string {
+	var modo string
+	prompt := &survey.Select{
+		Message: "Escolha o modo de jogo:",
+		Options: q.OpcoesMenu(),
+	}
+
+	survey.AskOne(prompt, &modo)
+	return modo
+}
+
+// Confirmar faz uma pergunta de sim ou não.
+func Confirmar(mensagem string, padrao bool) bool {
+	resposta := padrao
+	prompt := &survey.Confirm{
+		Message: mensagem,
+		Default: padrao,
+	}
+	survey.AskOne(prompt, &resposta)
+	return resposta
+}
+
+// Perguntar pede um texto curto ao jogador.
+func Perguntar(mensagem string) string {
+	var resposta string
+	survey.AskOne(&survey.Input{Message: mensagem}, &resposta)
+	return strings.TrimSpace(resposta)
+}
+
+// opcaoNovoPerfil é a opção do seletor de perfis que cria um perfil.
+const opcaoNovoPerfil = "➕ Criar um novo perfil"
+
+// EscolherPerfil mostra os perfis, com atual como padrão, e retorna o
+// escolhido, ou novo se o jogador pediu para criar um perfil. Retorna nome
+// vazio, sem novo, se ele desistiu com Ctrl+C.
+func EscolherPerfil(nomes []string, atual string) (nome string, novo bool) {
+	opcoes := make([]string, 0, len(nomes)+1)
+	for _, nome := range nomes {
+		opcoes = append(opcoes, "👤 "+nome)
+	}
+	opcoes = append(opcoes, opcaoNovoPerfil)
+
+	prompt := &survey.Select{
+		Message: "Quem vai jogar?",
+		Options: opcoes,
+	}
+	if atual != "" {
+		prompt.Default = "👤 " + atual
+	}
+	var escolhida string
+	survey.AskOne(prompt, &escolhida)
+	if escolhida == opcaoNovoPerfil {
+		return "", true
+	}
+	return strings.TrimPrefix(escolhida, "👤 "), false
+}
+
+func JogarNovamente() bool {
+	return Confirmar("Gostaria de jogar novamente?", false)
+}
+
+func (Terminal) Inicio(total int, duracaoProva time.Duration) {
+	fmt.Println()
+	fmt.Printf("%s Você terá %s questões para responder!\n",
+		ui.Magenta("📚"),
+		ui.Bold(fmt.Sprintf("%d", total)))
+	fmt.Println()
+
+	if duracaoProva > 0 {
+		fmt.Printf("%s Modo prova: você tem %s para responder tudo.\n",
+			ui.Magenta("⏱️"),
+			ui.Bold(duracaoProva.String()))
+		fmt.Println()
+	}
+}
+
+// Aguardando mostra um spinner enquanto a próxima questão é gerada.
+func (Terminal) Aguardando() func() {
+	spinner, _ := pterm.DefaultSpinner.Start(ui.Cyan("Aguardando a próxima questão..."))
+	return func() { spinner.Stop() }
+}
+
+func (Terminal) Questao(questao quiz.Questao, numero, total int) {
+	ui.LimparTela()
+	fmt.Println(ui.Cyan("═══════════════════════════════════════════════════════════"))
+	fmt.Printf("%s Questão %d de %d | %s | %s\n",
+		ui.Yellow("📝"),
+		numero,
+		total,
+		ui.Blue(fmt.Sprintf("Categoria: %s", questao.Categoria)),
+		iconeDificuldade(questao.Dificuldade))
+	fmt.Println(ui.Cyan("═══════════════════════════════════════════════════════════"))
+	fmt.Println()
+
+	_, codigos := separarEnunciado(questao)
+	for _, codigo := range codigos {
+		fmt.Println(ui.FormatarCodigo(codigo))
+		fmt.Println()
+	}
+}
+
+func (Terminal) Responder(questao quiz.Questao, prazo time.Time) ([]string, bool, error) {
+	return perguntarQuestao(questao, prazo)
+}
+
+func (Terminal) ErroResposta(err error) {
+	fmt.Printf(ui.Red("Erro ao ler resposta: %v\n"), err)
+}
+
+func (Terminal) Resultado(r quiz.Resultado) {
+	fmt.Println()
+
+	switch {
+	case r.Esgotado:
+		fmt.Printf(ui.Yellow("⏰ Tempo esgotado! A resposta correta é: %s\n"),
+			ui.Bold(r.Questao.RespostaEsperada()))
+	case r.Correta:
+		fmt.Println(ui.Green("✅ Resposta correta! Parabéns!"))
+	default:
+		fmt.Printf(ui.Red("❌ Resposta incorreta! A resposta correta é: %s\n"),
+			ui.Bold(r.Questao.RespostaEsperada()))
+	}
+
+	explicacao := ui.FormatarTexto(r.Questao.Explicacao)
+	if strings.Contains(explicacao, "\n") {
+		fmt.Println(ui.Blue("💡 Explicação:"))
+		fmt.Println(explicacao)
+	} else {
+		fmt.Printf("%s %s\n", ui.Blue("💡 Explicação:"), explicacao)
+	}
+	fmt.Println()
+}
+
+func (Terminal) Progresso(numero, total, acertos int) {
+	fmt.Printf(ui.Magenta("📊 Progresso: %d/%d questões | Acertos: %d\n"),
+		numero, total, acertos)
+	fmt.Println()
+}
+
+// Continuar pergunta se o jogador quer seguir. Na prova, o tempo continua
+// correndo entre as questões.
+func (Terminal) Continuar(prazo time.Time) (continuar, esgotado bool) {
+	continuePrompt := &survey.Confirm{
+		Message: "Continuar para a próxima questão?",
+		Default: true,
+	}
+	esgotado, _ = perguntar(continuePrompt, &continuar, prazo, nil)
+	if continuar {
+		fmt.Println()
+	}
+	return continuar, esgotado
+}
+
+func (Terminal) FimProva(semResposta int) {
+	fmt.Println()
+	fmt.Printf(ui.Yellow("⏰ Fim do tempo da prova! %d questões ficaram sem resposta.\n"), semResposta)
+	fmt.Println()
+}
+
+func (Terminal) Interrompido() {
+	fmt.Println(ui.Yellow("Quiz interrompido pelo usuário."))
+}
+
+func (Terminal) Resumo(r quiz.Resumo) {
+	MostrarResultados(r)
+}
+
+func iconeDificuldade(dificuldade string) string {
+	switch dificuldade {
+	case "facil":
+		return ui.Green("🟢 Fácil")
+	case "medio":
+		return ui.Yellow("🟡 Médio")
+	case "dificil":
+		return ui.Red("🔴 Difícil")
+	default:
+		return ui.Blue("🔵 Normal")
+	}
+}
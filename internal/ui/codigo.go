@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+)
+
+// Cores do destaque de sintaxe. Como as demais cores do pacote, somem
+// quando a saída não é um terminal ou NO_COLOR está definido.
+var (
+	corPalavraChave = color.New(color.FgMagenta).SprintFunc()
+	corTexto        = color.New(color.FgGreen).SprintFunc()
+	corNumero       = color.New(color.FgYellow).SprintFunc()
+	corComentario   = color.New(color.FgHiBlack).SprintFunc()
+	corPredeclarado = color.New(color.FgCyan).SprintFunc()
+	corMoldura      = color.New(color.FgHiBlack).SprintFunc()
+)
+
+const (
+	// larguraTab é quantos espaços substituem cada tab, para a moldura e a
+	// numeração ficarem alinhadas em qualquer terminal.
+	larguraTab = 4
+
+	// larguraMinimaMoldura e larguraMaximaMoldura limitam as linhas
+	// horizontais da moldura.
+	larguraMinimaMoldura = 20
+	larguraMaximaMoldura = 76
+)
+
+// Bloco é um trecho de um texto com blocos de código cercados por ```.
+type Bloco struct {
+	Texto  string
+	Codigo bool
+}
+
+// SepararBlocos divide um texto em trechos comuns e blocos de código
+// cercados por ``` (com ou sem linguagem, como ```go). Um bloco sem a cerca
+// de fechamento vai até o fim do texto.
+func SepararBlocos(texto string) []Bloco {
+	var blocos []Bloco
+	var atual []string
+	codigo := false
+	fechar := func() {
+		trecho := strings.Join(atual, "\n")
+		if codigo || strings.TrimSpace(trecho) != "" {
+			blocos = append(blocos, Bloco{Texto: trecho, Codigo: codigo})
+		}
+		atual = nil
+	}
+
+	for _, linha := range strings.Split(texto, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(linha), "```") {
+			fechar()
+			codigo = !codigo
+			continue
+		}
+		atual = append(atual, linha)
+	}
+	fechar()
+	return blocos
+}
+
+// FormatarTexto formata um texto para o terminal, desenhando os blocos de
+// código cercados por ``` com FormatarCodigo. Textos sem blocos voltam sem
+// mudança.
+func FormatarTexto(texto string) string {
+	blocos := SepararBlocos(texto)
+	if len(blocos) <= 1 && (len(blocos) == 0 || !blocos[0].Codigo) {
+		return texto
+	}
+
+	partes := make([]string, len(blocos))
+	for i, bloco := range blocos {
+		if bloco.Codigo {
+			partes[i] = FormatarCodigo(bloco.Texto)
+		} else {
+			partes[i] = strings.Trim(bloco.Texto, "\n")
+		}
+	}
+	return strings.Join(partes, "\n")
+}
+
+// FormatarCodigo desenha um trecho de código Go numa moldura, com as linhas
+// numeradas e a sintaxe destacada. Sem cores, só a moldura e a numeração
+// são mantidas.
+func FormatarCodigo(codigo string) string {
+	codigo = strings.Trim(strings.ReplaceAll(codigo, "\t", strings.Repeat(" ", larguraTab)), "\n")
+	linhas := strings.Split(codigo, "\n")
+	destacadas := strings.Split(destacarGo(codigo), "\n")
+	if len(destacadas) != len(linhas) {
+		destacadas = linhas
+	}
+
+	largura := larguraMinimaMoldura
+	for _, linha := range linhas {
+		largura = max(largura, utf8.RuneCountInString(linha)+2)
+	}
+	largura = min(largura, larguraMaximaMoldura)
+
+	digitos := len(fmt.Sprint(len(linhas)))
+	margem := strings.Repeat(" ", digitos+1)
+	horizontal := strings.Repeat("─", largura)
+
+	var b strings.Builder
+	b.WriteString(corMoldura(margem+"┌"+horizontal) + "\n")
+	for i, linha := range destacadas {
+		numero := fmt.Sprintf("%*d ", digitos, i+1)
+		b.WriteString(corMoldura(numero+"│") + " " + linha + "\n")
+	}
+	b.WriteString(corMoldura(margem + "└" + horizontal))
+	return b.String()
+}
+
+// destacarGo colore o código com o scanner da biblioteca padrão. Trechos que
+// não são Go válido também funcionam: o que o scanner não reconhece fica sem
+// cor.
+func destacarGo(codigo string) string {
+	if color.NoColor {
+		return codigo
+	}
+
+	fonte := []byte(codigo)
+	arquivo := token.NewFileSet().AddFile("", -1, len(fonte))
+	var s scanner.Scanner
+	s.Init(arquivo, fonte, func(token.Position, string) {}, scanner.ScanComments)
+
+	var b strings.Builder
+	ultimo := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		inicio := arquivo.Offset(pos)
+		if inicio < ultimo {
+			continue
+		}
+
+		var cor func(...any) string
+		switch {
+		case tok == token.SEMICOLON && lit == "\n":
+			// Ponto e vírgula inserido pelo scanner no fim da linha.
+			continue
+		case tok == token.COMMENT:
+			cor = corComentario
+		case tok == token.STRING || tok == token.CHAR:
+			cor = corTexto
+		case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+			cor = corNumero
+		case tok.IsKeyword():
+			cor = corPalavraChave
+		case tok == token.IDENT && types.Universe.Lookup(lit) != nil:
+			cor = corPredeclarado
+		default:
+			continue
+		}
+
+		texto := lit
+		if texto == "" {
+			texto = tok.String()
+		}
+		fim := min(inicio+len(texto), len(codigo))
+		b.WriteString(codigo[ultimo:inicio])
+		// Cada linha é colorida à parte, para o código poder ser dividido
+		// em linhas sem cores vazando para a moldura.
+		pedacos := strings.Split(codigo[inicio:fim], "\n")
+		for i, pedaco := range pedacos {
+			pedacos[i] = cor(pedaco)
+		}
+		b.WriteString(strings.Join(pedacos, "\n"))
+		ultimo = fim
+	}
+	b.WriteString(codigo[ultimo:])
+	return b.String()
+}
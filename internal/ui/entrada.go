@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"os"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2/terminal"
+)
+
+// EntradaComPrazo substitui os.Stdin numa pergunta do survey (veja
+// survey.WithStdio) para limitar o tempo de resposta. Quando o prazo acaba,
+// entrega um Ctrl+C e o survey encerra a pergunta com terminal.InterruptErr.
+//
+// No Windows o survey lê o console sem passar pela entrada, então a pergunta
+// não é interrompida; quem chama deve conferir o prazo depois da resposta.
+type EntradaComPrazo struct {
+	Prazo time.Time
+
+	// AoTique, se informado, é chamado a cada segundo com o tempo restante,
+	// enquanto nenhuma tecla é pressionada, e a pergunta é redesenhada em
+	// seguida. Serve para atualizar uma contagem regressiva na mensagem.
+	AoTique func(restante time.Duration)
+}
+
+func (e *EntradaComPrazo) Fd() uintptr {
+	return os.Stdin.Fd()
+}
+
+func (e *EntradaComPrazo) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for {
+		restante := time.Until(e.Prazo)
+		if restante <= 0 {
+			p[0] = terminal.KeyInterrupt
+			return 1, nil
+		}
+
+		// Acorda quando o segundo mostrado na contagem muda.
+		espera := restante
+		if e.AoTique != nil {
+			espera = restante % time.Second
+			if espera == 0 {
+				espera = time.Second
+			}
+		}
+
+		pronta, err := esperarEntrada(os.Stdin, espera)
+		if err != nil {
+			return 0, err
+		}
+		if pronta {
+			return os.Stdin.Read(p)
+		}
+
+		if restante = time.Until(e.Prazo); e.AoTique != nil && restante > 0 {
+			e.AoTique(restante)
+			// Uma tecla que Select e MultiSelect ignoram, só para redesenhar
+			// a pergunta.
+			p[0] = terminal.IgnoreKey
+			return 1, nil
+		}
+	}
+}
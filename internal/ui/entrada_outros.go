@@ -0,0 +1,14 @@
+//go:build !unix
+
+package ui
+
+import (
+	"os"
+	"time"
+)
+
+// esperarEntrada não consegue esperar com limite fora de sistemas Unix; a
+// leitura fica bloqueada até o jogador responder.
+func esperarEntrada(arquivo *os.File, espera time.Duration) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,25 @@
+//go:build unix
+
+package ui
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// esperarEntrada espera até que haja algo para ler em arquivo, por no
+// máximo espera.
+func esperarEntrada(arquivo *os.File, espera time.Duration) (bool, error) {
+	fd := int(arquivo.Fd())
+	var leitura unix.FdSet
+	leitura.Set(fd)
+	limite := unix.NsecToTimeval(espera.Nanoseconds())
+
+	n, err := unix.Select(fd+1, &leitura, nil, nil, &limite)
+	if err == unix.EINTR {
+		return false, nil
+	}
+	return n > 0, err
+}